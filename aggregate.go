@@ -0,0 +1,122 @@
+package gocrud
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Count returns the number of rows matching filter, e.g. sq.Eq{"status": "active"}, or of all
+// rows if filter is nil.
+func (r *Repository[M, K]) Count(ctx context.Context, filter sq.Sqlizer) (int64, error) {
+	var n int64
+	err := r.aggregate(ctx, "COUNT(*)", filter, &n)
+
+	return n, err
+}
+
+// Sum returns the sum of column over the rows matching filter, 0 if there are none.
+func (r *Repository[M, K]) Sum(ctx context.Context, column string, filter sq.Sqlizer) (float64, error) {
+	var sum sql.NullFloat64
+	err := r.aggregate(ctx, "SUM("+column+")", filter, &sum)
+
+	return sum.Float64, err
+}
+
+// Exists reports whether a row with the given key exists.
+func (r *Repository[M, K]) Exists(ctx context.Context, id K) (bool, error) {
+	eq, err := r.keyEq(id)
+	if err != nil {
+		return false, err
+	}
+
+	var n int64
+	if err := r.aggregate(ctx, "COUNT(*)", eq, &n); err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+func (r *Repository[M, K]) aggregate(ctx context.Context, expr string, filter sq.Sqlizer, dest any) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	b := r.scope(r.builder().Select(expr).From(r.table))
+	if filter != nil {
+		b = b.Where(filter)
+	}
+
+	query, args, err := b.ToSql()
+	if err != nil {
+		return err
+	}
+
+	return r.db.QueryRowContext(ctx, query, args...).Scan(dest)
+}
+
+// GroupBy returns a query yielding one row of D per distinct value of columns, along with the
+// given aggregate expressions, e.g.
+//
+//	GroupBy(orders, newStatusTotal, []string{"status"}, "COUNT(*) AS orders", "SUM(total) AS total")
+//
+// The aggregates need aliases matching the columns mapped by D.
+func GroupBy[D Model, M Model, K comparable](r *Repository[M, K], newRow func() D, columns []string, aggregates ...string) *SelectQuery[D] {
+	return Select(r, newRow, append(slices.Clone(columns), aggregates...)...).
+		GroupBy(columns...).
+		OrderBy(columns...)
+}
+
+// WithTotalCount makes RegisterGetAll report the number of rows given by count, typically
+// Repository.Count: in the X-Total-Count header of list responses, or as {"count": n} alone
+// when the request has ?count_only=true.
+func WithTotalCount(count func(ctx context.Context, filter sq.Sqlizer) (int64, error)) HandlerOption {
+	return func(c *handlerConfig) {
+		c.count = count
+	}
+}
+
+// countOnly answers a ?count_only=true request with the total count, reporting whether it did.
+func (c *handlerConfig) countOnly(w http.ResponseWriter, r *http.Request) bool {
+	if c.count == nil || r.URL.Query().Get("count_only") != "true" {
+		return false
+	}
+
+	n, err := c.count(r.Context(), nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Total-Count", strconv.FormatInt(n, 10))
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(map[string]int64{"count": n}); err != nil {
+		log.Printf("failed to encode count: %v", err)
+	}
+
+	return true
+}
+
+// totalCount sets the X-Total-Count header. If counting fails it writes the error response and
+// returns false.
+func (c *handlerConfig) totalCount(w http.ResponseWriter, r *http.Request) bool {
+	if c.count == nil {
+		return true
+	}
+
+	n, err := c.count(r.Context(), nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	w.Header().Set("X-Total-Count", strconv.FormatInt(n, 10))
+
+	return true
+}
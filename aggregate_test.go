@@ -0,0 +1,82 @@
+package gocrud
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+)
+
+type TypeCount struct {
+	Type  string `json:"type"`
+	Total int64  `json:"total"`
+	Reflection
+}
+
+func TestAggregate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewGenericRepository(db, "devices", func() *ModelWithReflection { return &ModelWithReflection{} }, WithSoftDelete())
+
+	t.Run("Test Count()", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM devices WHERE deleted_at IS NULL AND type = ?")).
+			WithArgs("sensor").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		got, err := repo.Count(context.Background(), sq.Eq{"type": "sensor"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, int64(3), got)
+	})
+
+	t.Run("Test Sum() without rows", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT SUM(id) FROM devices WHERE deleted_at IS NULL")).
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(nil))
+
+		got, err := repo.Sum(context.Background(), "id", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, float64(0), got)
+	})
+
+	t.Run("Test Exists()", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM devices WHERE deleted_at IS NULL AND id = ?")).
+			WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		got, err := repo.Exists(context.Background(), 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.False(t, got)
+	})
+
+	t.Run("Test GroupBy()", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT type, COUNT(*) AS total FROM devices WHERE devices.deleted_at IS NULL GROUP BY type HAVING COUNT(*) > ? ORDER BY type")).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"type", "total"}).AddRow("relay", 2).AddRow("sensor", 5))
+
+		got, err := GroupBy(repo, func() *TypeCount { return &TypeCount{} }, []string{"type"}, "COUNT(*) AS total").
+			Having("COUNT(*) > ?", 1).
+			All(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []*TypeCount{{Type: "relay", Total: 2}, {Type: "sensor", Total: 5}}, got)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,113 @@
+package gocrud
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+
+	"github.com/lib/pq"
+)
+
+// adaptColumn wraps slice fields that the driver cannot handle natively: as a native array
+// on dialects that support them, or as a JSON document otherwise. []byte and fields that
+// already implement sql.Scanner are left untouched.
+func adaptColumn(d Dialect, p any) any {
+	if _, ok := p.(sql.Scanner); ok {
+		return p
+	}
+
+	v := reflect.ValueOf(p)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Slice || v.Elem().Type().Elem().Kind() == reflect.Uint8 {
+		return p
+	}
+
+	if d.Arrays {
+		return postgresArray{field: v.Elem()}
+	}
+
+	return jsonColumn{field: v.Elem()}
+}
+
+// postgresArray stores a slice field as a PostgreSQL array, converting named element types
+// (e.g. type Tag string) to the matching lib/pq array type.
+type postgresArray struct {
+	field reflect.Value
+}
+
+func (a postgresArray) typed() (reflect.Value, bool) {
+	var base any
+
+	switch a.field.Type().Elem().Kind() {
+	case reflect.String:
+		base = pq.StringArray{}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		base = pq.Int64Array{}
+	case reflect.Float32, reflect.Float64:
+		base = pq.Float64Array{}
+	case reflect.Bool:
+		base = pq.BoolArray{}
+	default:
+		return reflect.Value{}, false
+	}
+
+	return reflect.New(reflect.TypeOf(base)), true
+}
+
+func (a postgresArray) Scan(src any) error {
+	tmp, ok := a.typed()
+	if !ok {
+		return pq.GenericArray{A: a.field.Addr().Interface()}.Scan(src)
+	}
+
+	scanner, ok := tmp.Interface().(sql.Scanner)
+	if !ok {
+		return fmt.Errorf("%s is not a scanner", tmp.Type())
+	}
+
+	if err := scanner.Scan(src); err != nil {
+		return err
+	}
+
+	values := tmp.Elem()
+	if values.IsNil() {
+		a.field.SetZero()
+		return nil
+	}
+
+	out := reflect.MakeSlice(a.field.Type(), values.Len(), values.Len())
+	for i := range values.Len() {
+		out.Index(i).Set(values.Index(i).Convert(a.field.Type().Elem()))
+	}
+	a.field.Set(out)
+
+	return nil
+}
+
+func (a postgresArray) Value() (driver.Value, error) {
+	tmp, ok := a.typed()
+	if !ok {
+		return pq.GenericArray{A: a.field.Interface()}.Value()
+	}
+
+	if a.field.IsNil() {
+		return nil, nil
+	}
+
+	values := reflect.MakeSlice(tmp.Elem().Type(), a.field.Len(), a.field.Len())
+	for i := range a.field.Len() {
+		values.Index(i).Set(a.field.Index(i).Convert(values.Type().Elem()))
+	}
+
+	valuer, ok := values.Interface().(driver.Valuer)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a valuer", values.Type())
+	}
+
+	return valuer.Value()
+}
+
+func (a postgresArray) fieldPtr() any {
+	return a.field.Addr().Interface()
+}
@@ -0,0 +1,82 @@
+package gocrud
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+type tag string
+
+func TestArray_AdaptColumn(t *testing.T) {
+	t.Run("Test adaptColumn native array", func(t *testing.T) {
+		var s []string
+		assert.IsType(t, postgresArray{}, adaptColumn(Postgres, &s))
+	})
+
+	t.Run("Test adaptColumn JSON array", func(t *testing.T) {
+		var s []string
+		assert.IsType(t, jsonColumn{}, adaptColumn(SQLite, &s))
+	})
+
+	t.Run("Test adaptColumn leaves bytes and scanners", func(t *testing.T) {
+		var b []byte
+		var s []string
+		assert.Equal(t, &b, adaptColumn(Postgres, &b))
+		assert.Equal(t, (*pq.StringArray)(&s), adaptColumn(Postgres, (*pq.StringArray)(&s)))
+	})
+}
+
+func TestArray_PostgresArray(t *testing.T) {
+	t.Run("Test postgresArray scan ints", func(t *testing.T) {
+		var s []int
+		err := postgresArray{field: reflect.ValueOf(&s).Elem()}.Scan([]byte("{1,2,3}"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []int{1, 2, 3}, s)
+	})
+
+	t.Run("Test postgresArray scan named strings", func(t *testing.T) {
+		var s []tag
+		err := postgresArray{field: reflect.ValueOf(&s).Elem()}.Scan([]byte(`{a,"b c"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []tag{"a", "b c"}, s)
+	})
+
+	t.Run("Test postgresArray scan NULL", func(t *testing.T) {
+		s := []string{"a"}
+		err := postgresArray{field: reflect.ValueOf(&s).Elem()}.Scan(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Nil(t, s)
+	})
+
+	t.Run("Test postgresArray value", func(t *testing.T) {
+		s := []tag{"a", "b c"}
+		got, err := postgresArray{field: reflect.ValueOf(&s).Elem()}.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, `{"a","b c"}`, got)
+	})
+
+	t.Run("Test postgresArray nil value", func(t *testing.T) {
+		var s []int
+		got, err := postgresArray{field: reflect.ValueOf(&s).Elem()}.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Nil(t, got)
+	})
+}
@@ -0,0 +1,252 @@
+package gocrud
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Association manages the join table linking the rows of a repository to those of a related
+// repository, e.g. devices and tags through device_tags(device_id, tag_id).
+type Association[K comparable, R Model, RK comparable] struct {
+	db        *sql.DB
+	dialect   Dialect
+	joinTable string
+	fk        string
+	relatedFK string
+	// relatedKey is the primary key column of related.
+	relatedKey string
+	related    *Repository[R, RK]
+}
+
+// ManyToMany declares a many-to-many relation through joinTable, whose fk column references
+// repo and relatedFK column references related. Preloading field assigns the related models to
+// that field of the model, a slice of R or of the type R points to, tagged `db:"-"`. It panics
+// if either repository has a composite primary key.
+func ManyToMany[M Model, K comparable, R Model, RK comparable](repo *Repository[M, K], field string, related *Repository[R, RK], joinTable, fk, relatedFK string) *Association[K, R, RK] {
+	a := &Association[K, R, RK]{
+		db:         repo.db,
+		dialect:    repo.config.dialect,
+		joinTable:  joinTable,
+		fk:         fk,
+		relatedFK:  relatedFK,
+		relatedKey: related.keyColumn("ManyToMany"),
+		related:    related,
+	}
+
+	repo.relate(field, relation{
+		column: repo.keyColumn("ManyToMany"),
+		many:   true,
+		fetch:  a.fetch,
+	})
+
+	return a
+}
+
+// ListRelated returns the related models linked to the row with key id, ordered by key.
+func (a *Association[K, R, RK]) ListRelated(ctx context.Context, id K) ([]R, error) {
+	linked := sq.Select(a.relatedFK).From(a.joinTable).Where(sq.Eq{a.fk: id})
+
+	return a.related.list(ctx, sq.Expr(a.relatedKey+" IN (?)", linked))
+}
+
+// Attach links the row with key id to the related rows, skipping existing links.
+func (a *Association[K, R, RK]) Attach(ctx context.Context, id K, related ...RK) error {
+	return a.transaction(ctx, func(tx *sql.Tx) error {
+		current, err := a.linked(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		return a.insert(ctx, tx, id, missing(related, current))
+	})
+}
+
+// Detach removes the links between the row with key id and the related rows.
+func (a *Association[K, R, RK]) Detach(ctx context.Context, id K, related ...RK) error {
+	if len(related) == 0 {
+		return nil
+	}
+
+	return a.transaction(ctx, func(tx *sql.Tx) error {
+		return a.delete(ctx, tx, id, related)
+	})
+}
+
+// Sync makes the related rows the only ones linked to the row with key id.
+func (a *Association[K, R, RK]) Sync(ctx context.Context, id K, related []RK) error {
+	return a.transaction(ctx, func(tx *sql.Tx) error {
+		current, err := a.linked(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := a.delete(ctx, tx, id, missing(current, related)); err != nil {
+			return err
+		}
+
+		return a.insert(ctx, tx, id, missing(related, current))
+	})
+}
+
+func (a *Association[K, R, RK]) builder() sq.StatementBuilderType {
+	return sq.StatementBuilder.PlaceholderFormat(a.dialect.Placeholder)
+}
+
+func (a *Association[K, R, RK]) transaction(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// linked returns the keys of the related rows currently linked to id.
+func (a *Association[K, R, RK]) linked(ctx context.Context, tx *sql.Tx, id K) ([]RK, error) {
+	query, args, err := a.builder().Select(a.relatedFK).From(a.joinTable).Where(sq.Eq{a.fk: id}).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []RK
+	for rows.Next() {
+		var key RK
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (a *Association[K, R, RK]) insert(ctx context.Context, tx *sql.Tx, id K, related []RK) error {
+	if len(related) == 0 {
+		return nil
+	}
+
+	b := a.builder().Insert(a.joinTable).Columns(a.fk, a.relatedFK)
+	for _, key := range related {
+		b = b.Values(id, key)
+	}
+
+	query, args, err := b.ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, query, args...)
+
+	return err
+}
+
+func (a *Association[K, R, RK]) delete(ctx context.Context, tx *sql.Tx, id K, related []RK) error {
+	if len(related) == 0 {
+		return nil
+	}
+
+	query, args, err := a.builder().Delete(a.joinTable).
+		Where(sq.Eq{a.fk: id}).
+		Where(sq.Eq{a.relatedFK: related}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// fetch loads the related models linked to any of the given keys, for Preload. A related model
+// linked to several keys is returned once per key.
+func (a *Association[K, R, RK]) fetch(ctx context.Context, values []any) ([]Model, []any, error) {
+	query, args, err := a.builder().Select(a.fk, a.relatedFK).From(a.joinTable).
+		Where(sq.Eq{a.fk: values}).ToSql()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := a.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var owners []any
+	var targets []RK
+	for rows.Next() {
+		var owner any
+		var target RK
+		if err := rows.Scan(&owner, &target); err != nil {
+			return nil, nil, err
+		}
+		owners = append(owners, owner)
+		targets = append(targets, target)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(targets) == 0 {
+		return nil, nil, nil
+	}
+
+	models, err := a.related.list(ctx, sq.Eq{a.relatedKey: uniq(targets)})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byKey := make(map[string]R, len(models))
+	for _, m := range models {
+		key, _ := columnValue(a.related.fields(m)[a.relatedKey])
+		byKey[matchKey(key)] = m
+	}
+
+	related := make([]Model, 0, len(targets))
+	keys := make([]any, 0, len(targets))
+	for i, target := range targets {
+		if m, ok := byKey[matchKey(target)]; ok {
+			related = append(related, m)
+			keys = append(keys, owners[i])
+		}
+	}
+
+	return related, keys, nil
+}
+
+// missing returns the keys of want that are not in have.
+func missing[T comparable](want, have []T) []T {
+	var out []T
+	for _, key := range uniq(want) {
+		if !slices.Contains(have, key) {
+			out = append(out, key)
+		}
+	}
+
+	return out
+}
+
+func uniq[T comparable](keys []T) []T {
+	out := make([]T, 0, len(keys))
+	for _, key := range keys {
+		if !slices.Contains(out, key) {
+			out = append(out, key)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,130 @@
+package gocrud
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type Tag struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Reflection
+}
+
+type TaggedDevice struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Tags []Tag  `json:"tags,omitempty" db:"-"`
+	Reflection
+}
+
+func TestAssociation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	devices := NewGenericRepository(db, "devices", func() *TaggedDevice { return &TaggedDevice{} }, WithDialect(Postgres))
+	tags := NewGenericRepository(db, "tags", func() *Tag { return &Tag{} }, WithDialect(Postgres))
+	deviceTags := ManyToMany(devices, "Tags", tags, "device_tags", "device_id", "tag_id")
+
+	linked := regexp.QuoteMeta("SELECT tag_id FROM device_tags WHERE device_id = $1")
+
+	t.Run("Test Attach() skips existing links", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(linked).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"tag_id"}).AddRow(2))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO device_tags (device_id,tag_id) VALUES ($1,$2),($3,$4)")).
+			WithArgs(1, 3, 1, 4).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		assert.NoError(t, deviceTags.Attach(context.Background(), 1, 2, 3, 4, 3))
+	})
+
+	t.Run("Test Detach()", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM device_tags WHERE device_id = $1 AND tag_id IN ($2,$3)")).
+			WithArgs(1, 2, 3).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		assert.NoError(t, deviceTags.Detach(context.Background(), 1, 2, 3))
+	})
+
+	t.Run("Test Sync()", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(linked).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"tag_id"}).AddRow(2).AddRow(3))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM device_tags WHERE device_id = $1 AND tag_id IN ($2)")).
+			WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO device_tags (device_id,tag_id) VALUES ($1,$2)")).
+			WithArgs(1, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, deviceTags.Sync(context.Background(), 1, []int{3, 5}))
+	})
+
+	t.Run("Test Sync() rolls back on error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(linked).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"tag_id"}).AddRow(2))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM device_tags")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO device_tags")).WillReturnError(assert.AnError)
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, deviceTags.Sync(context.Background(), 1, []int{9}), assert.AnError)
+	})
+
+	t.Run("Test ListRelated()", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM tags WHERE id IN (SELECT tag_id FROM device_tags WHERE device_id = $1) ORDER BY id")).
+			WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "lab").AddRow(3, "prod"))
+
+		got, err := deviceTags.ListRelated(context.Background(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []*Tag{{ID: 2, Name: "lab"}, {ID: 3, Name: "prod"}}, got)
+	})
+
+	t.Run("Test Preload() many-to-many", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM devices ORDER BY id")).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "esp32").AddRow(2, "pico"))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT device_id, tag_id FROM device_tags WHERE device_id IN ($1,$2)")).
+			WithArgs(1, 2).WillReturnRows(sqlmock.NewRows([]string{"device_id", "tag_id"}).AddRow(1, 2).AddRow(2, 2).AddRow(1, 3))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM tags WHERE id IN ($1,$2) ORDER BY id")).
+			WithArgs(2, 3).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "lab").AddRow(3, "prod"))
+
+		got, err := devices.Preload("Tags").GetAll(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []*TaggedDevice{
+			{ID: 1, Name: "esp32", Tags: []Tag{{ID: 2, Name: "lab"}, {ID: 3, Name: "prod"}}},
+			{ID: 2, Name: "pico", Tags: []Tag{{ID: 2, Name: "lab"}}},
+		}, got)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAssociation_CompositeKey(t *testing.T) {
+	products := NewRepository[*Product, SKUKey](nil, "products", func() *Product { return &Product{} }, WithPrimaryKey("tenant_id", "sku"))
+	tags := NewGenericRepository(nil, "tags", func() *Tag { return &Tag{} })
+
+	t.Run("Test ManyToMany() from a composite key", func(t *testing.T) {
+		assert.Panics(t, func() {
+			ManyToMany(products, "Tags", tags, "product_tags", "product_id", "tag_id")
+		})
+	})
+
+	t.Run("Test ManyToMany() to a composite key", func(t *testing.T) {
+		assert.PanicsWithValue(t, "gocrud: ManyToMany on products: relations need a single-column primary key, not [tenant_id sku]", func() {
+			ManyToMany(tags, "Products", products, "product_tags", "tag_id", "product_id")
+		})
+	})
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	directive  = "//gocrud:model"
+	importPath = "github.com/tender-barbarian/go-crud"
+)
+
+type field struct {
+	Name     string
+	Column   string
+	JSON     bool
+	NullZero bool
+	Key      bool
+}
+
+type model struct {
+	Name   string
+	Fields []field
+	// Reflection reports whether the struct embeds gocrud.Reflection, whose StructToMap would
+	// conflict with a generated one.
+	Reflection bool
+}
+
+func (m model) KeyFields() []field {
+	var keys []field
+	for _, f := range m.Fields {
+		if f.Key {
+			keys = append(keys, f)
+		}
+	}
+
+	return keys
+}
+
+// parseDir collects the requested models from the non-test Go files in dir.
+func parseDir(dir string, types []string) (string, []model, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fset := token.NewFileSet()
+	pkg := ""
+	var models []model
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || strings.HasSuffix(name, "_gocrud.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return "", nil, err
+		}
+		pkg = file.Name.Name
+
+		models = append(models, parseFile(file, types)...)
+	}
+
+	for _, name := range types {
+		if !slices.ContainsFunc(models, func(m model) bool { return m.Name == name }) {
+			return "", nil, fmt.Errorf("type %s not found", name)
+		}
+	}
+
+	models = slices.DeleteFunc(models, func(m model) bool {
+		if m.Reflection {
+			log.Printf("gocrud-gen: skipping %s: it embeds gocrud.Reflection", m.Name)
+		}
+		return m.Reflection
+	})
+
+	slices.SortFunc(models, func(a, b model) int { return strings.Compare(a.Name, b.Name) })
+
+	return pkg, models, nil
+}
+
+func parseFile(file *ast.File, types []string) []model {
+	var models []model
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			doc := ts.Doc
+			if doc == nil {
+				doc = gen.Doc
+			}
+			if !slices.Contains(types, ts.Name.Name) && (len(types) > 0 || !hasDirective(doc)) {
+				continue
+			}
+
+			models = append(models, parseStruct(ts.Name.Name, st))
+		}
+	}
+
+	return models
+}
+
+func hasDirective(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+
+	return slices.ContainsFunc(doc.List, func(c *ast.Comment) bool {
+		return strings.TrimSpace(c.Text) == directive
+	})
+}
+
+func parseStruct(name string, st *ast.StructType) model {
+	m := model{Name: name}
+
+	for _, f := range st.Fields.List {
+		// Embedded fields such as gocrud.Reflection are not columns.
+		if len(f.Names) == 0 {
+			if sel, ok := f.Type.(*ast.SelectorExpr); ok && sel.Sel.Name == "Reflection" {
+				m.Reflection = true
+			}
+			continue
+		}
+
+		tag := ""
+		if f.Tag != nil {
+			if unquoted, err := strconv.Unquote(f.Tag.Value); err == nil {
+				tag = reflect.StructTag(unquoted).Get("db")
+			}
+		}
+
+		column, rest, _ := strings.Cut(tag, ",")
+		if column == "-" {
+			continue
+		}
+		options := strings.Split(rest, ",")
+
+		for _, ident := range f.Names {
+			if !ident.IsExported() {
+				continue
+			}
+
+			c := column
+			if c == "" {
+				c = strings.ToLower(ident.Name)
+			}
+
+			m.Fields = append(m.Fields, field{
+				Name:     ident.Name,
+				Column:   c,
+				JSON:     slices.Contains(options, "json"),
+				NullZero: slices.Contains(options, "nullzero"),
+				Key:      slices.Contains(options, "pk"),
+			})
+		}
+	}
+
+	if len(m.KeyFields()) == 0 {
+		for i := range m.Fields {
+			if m.Fields[i].Column == "id" {
+				m.Fields[i].Key = true
+			}
+		}
+	}
+
+	return m
+}
+
+var tmpl = template.Must(template.New("gocrud").Parse(`// Code generated by gocrud-gen. DO NOT EDIT.
+
+package {{.Package}}
+{{if .Import}}
+import gocrud "` + importPath + `"
+{{end}}
+{{- range .Models}}
+{{$m := .}}
+func (m *{{.Name}}) StructToMap(interface{}) map[string]any {
+	return map[string]any{
+{{- range .Fields}}
+		"{{.Column}}": {{if .JSON}}gocrud.JSONColumn(&m.{{.Name}}){{else if .NullZero}}gocrud.NullZero(&m.{{.Name}}){{else}}&m.{{.Name}}{{end}},
+{{- end}}
+	}
+}
+
+// Columns returns the columns of {{.Name}} in field order.
+func (*{{.Name}}) Columns() []string {
+	return []string{ {{- range $i, $f := .Fields}}{{if $i}}, {{end}}"{{$f.Column}}"{{end -}} }
+}
+
+// KeyColumns returns the primary key columns of {{.Name}}.
+func (*{{.Name}}) KeyColumns() []string {
+	return []string{ {{- range $i, $f := .KeyFields}}{{if $i}}, {{end}}"{{$f.Column}}"{{end -}} }
+}
+
+// KeyValues returns the primary key values of {{.Name}}, in KeyColumns order.
+func (m *{{.Name}}) KeyValues() []any {
+	return []any{ {{- range $i, $f := .KeyFields}}{{if $i}}, {{end}}m.{{$f.Name}}{{end -}} }
+}
+{{- end}}
+`))
+
+// generate renders and formats the source file for the models of package pkg.
+func generate(pkg string, models []model) ([]byte, error) {
+	useImport := false
+	for _, m := range models {
+		for _, f := range m.Fields {
+			useImport = useImport || f.JSON || f.NullZero
+		}
+	}
+
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, struct {
+		Package string
+		Import  bool
+		Models  []model
+	}{Package: pkg, Import: useImport, Models: models})
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, buf.Bytes())
+	}
+
+	return src, nil
+}
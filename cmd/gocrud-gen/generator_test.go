@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerator_ParseDir(t *testing.T) {
+	t.Run("Test parseDir with directives", func(t *testing.T) {
+		pkg, models, err := parseDir("testdata", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "models", pkg)
+		assert.Equal(t, []model{
+			{Name: "Device", Fields: []field{
+				{Name: "ID", Column: "id", Key: true},
+				{Name: "Name", Column: "name"},
+				{Name: "Board", Column: "board", NullZero: true},
+				{Name: "Settings", Column: "settings", JSON: true},
+			}},
+			{Name: "Product", Fields: []field{
+				{Name: "TenantID", Column: "tenant_id", Key: true},
+				{Name: "SKU", Column: "sku", Key: true},
+				{Name: "Name", Column: "name"},
+			}},
+		}, models)
+	})
+
+	t.Run("Test parseDir with -type", func(t *testing.T) {
+		_, models, err := parseDir("testdata", []string{"Ignored"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []model{{Name: "Ignored", Fields: []field{{Name: "ID", Column: "id", Key: true}}}}, models)
+	})
+
+	t.Run("Test parseDir skips models embedding Reflection", func(t *testing.T) {
+		_, models, err := parseDir("testdata", []string{"Legacy"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Empty(t, models)
+	})
+
+	t.Run("Test parseDir unknown type", func(t *testing.T) {
+		_, _, err := parseDir("testdata", []string{"Missing"})
+		assert.Error(t, err)
+	})
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	pkg, models, err := parseDir("testdata", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := generate(pkg, models)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile("testdata/models_gocrud.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, string(want), string(got))
+}
@@ -0,0 +1,64 @@
+// Command gocrud-gen generates StructToMap implementations for go-crud models, so they can be
+// used without reflection while staying in sync with the struct definition.
+//
+// Add a directive next to the models and run go generate:
+//
+//	//go:generate go run github.com/tender-barbarian/go-crud/cmd/gocrud-gen -type=Device,Order
+//
+// Without -type, every struct whose doc comment contains a //gocrud:model line is generated.
+// Column names and options follow the `db` tag rules of gocrud.Reflection, and the `pk` tag
+// option marks primary key fields (the "id" column by default). gocrud.NewRepository reads the
+// generated KeyColumns, so WithPrimaryKey is not needed. Structs embedding gocrud.Reflection are
+// skipped with a warning, since their StructToMap would clash with the generated one.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	types := flag.String("type", "", "comma-separated list of struct names")
+	output := flag.String("output", "", "output file name; default <package>_gocrud.go")
+	flag.Parse()
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	var names []string
+	if *types != "" {
+		names = strings.Split(*types, ",")
+	}
+
+	pkg, models, err := parseDir(dir, names)
+	if err != nil {
+		log.Fatalf("gocrud-gen: %v", err)
+	}
+	if len(models) == 0 {
+		log.Fatalf("gocrud-gen: no models found in %s", dir)
+	}
+
+	src, err := generate(pkg, models)
+	if err != nil {
+		log.Fatalf("gocrud-gen: %v", err)
+	}
+
+	name := *output
+	if name == "" {
+		name = fmt.Sprintf("%s_gocrud.go", pkg)
+	}
+
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(dir, name)
+	}
+
+	if err := os.WriteFile(name, src, 0o600); err != nil {
+		log.Fatalf("gocrud-gen: %v", err)
+	}
+}
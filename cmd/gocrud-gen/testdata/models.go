@@ -0,0 +1,38 @@
+package models
+
+import gocrud "github.com/tender-barbarian/go-crud"
+
+type Settings struct {
+	Theme string `json:"theme"`
+}
+
+//gocrud:model
+type Device struct {
+	ID       int      `json:"id"`
+	Name     string   `json:"name"`
+	Board    string   `json:"board" db:"board,nullzero"`
+	Settings Settings `json:"settings" db:"settings,json"`
+	Secret   string   `db:"-"`
+	internal string
+}
+
+// Legacy already maps its columns through reflection, so it is skipped.
+//
+//gocrud:model
+type Legacy struct {
+	ID int
+	gocrud.Reflection
+}
+
+// Product is identified by tenant and SKU.
+//
+//gocrud:model
+type Product struct {
+	TenantID int    `db:"tenant_id,pk"`
+	SKU      string `db:"sku,pk"`
+	Name     string
+}
+
+type Ignored struct {
+	ID int
+}
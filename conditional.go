@@ -0,0 +1,179 @@
+package gocrud
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// ETagger lets a model supply its own entity tag, e.g. derived from a version column.
+type ETagger interface {
+	ETag() string
+}
+
+// LastModifier lets a model expose a modification time for the Last-Modified header.
+type LastModifier interface {
+	LastModified() time.Time
+}
+
+type HandlerOption func(*handlerConfig)
+
+type handlerConfig struct {
+	parseKey       func(*http.Request) (any, error)
+	currentETag    func(ctx context.Context, id any) (string, error)
+	requireIfMatch bool
+	count          func(ctx context.Context, filter sq.Sqlizer) (int64, error)
+	bestEffort     bool
+	batchSize      int
+	maxUploadSize  int64
+}
+
+func newHandlerConfig(opts []HandlerOption) *handlerConfig {
+	c := &handlerConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// WithIfMatch makes RegisterUpdate and RegisterDelete compare the If-Match header against
+// the ETag of the resource returned by get, responding with 412 on mismatch.
+//
+// The check runs before the write and is not atomic with it: two requests carrying the same
+// valid ETag can both pass. To guard against lost updates, combine it with WithVersionColumn,
+// so that Update itself only matches the version the client read.
+func WithIfMatch[M Model, K any](get func(ctx context.Context, id K) (M, error)) HandlerOption {
+	return func(c *handlerConfig) {
+		c.currentETag = func(ctx context.Context, id any) (string, error) {
+			key, ok := id.(K)
+			if !ok {
+				return "", fmt.Errorf("if-match getter expects %T key, got %T", key, id)
+			}
+
+			model, err := get(ctx, key)
+			if err != nil {
+				return "", err
+			}
+
+			tag, _, err := entityTag(model)
+
+			return tag, err
+		}
+	}
+}
+
+// RequireIfMatch is WithIfMatch that also rejects requests without an If-Match header with 428.
+func RequireIfMatch[M Model, K any](get func(ctx context.Context, id K) (M, error)) HandlerOption {
+	withIfMatch := WithIfMatch(get)
+
+	return func(c *handlerConfig) {
+		withIfMatch(c)
+		c.requireIfMatch = true
+	}
+}
+
+// entityTag returns the strong ETag of v along with its JSON encoding.
+func entityTag(v any) (string, []byte, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if t, ok := v.(ETagger); ok {
+		return quoteETag(t.ETag()), body, nil
+	}
+
+	sum := sha256.Sum256(body)
+
+	return quoteETag(hex.EncodeToString(sum[:16])), body, nil
+}
+
+func quoteETag(tag string) string {
+	if strings.HasPrefix(tag, `"`) || strings.HasPrefix(tag, `W/"`) {
+		return tag
+	}
+
+	return `"` + tag + `"`
+}
+
+// matchETag reports whether tag is listed in an If-Match or If-None-Match header value.
+// Weak comparison is used when weak is true.
+func matchETag(header, tag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+			tag = strings.TrimPrefix(tag, "W/")
+		} else if strings.HasPrefix(candidate, "W/") || strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		if candidate == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// notModified reports whether a GET can be answered with 304 for the given validators.
+func notModified(r *http.Request, tag string, modified time.Time) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		return matchETag(header, tag, true)
+	}
+
+	if header := r.Header.Get("If-Modified-Since"); header != "" && !modified.IsZero() {
+		since, err := http.ParseTime(header)
+		if err != nil {
+			return false
+		}
+		return !modified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// checkIfMatch evaluates the If-Match precondition and writes the error response if it fails.
+func (c *handlerConfig) checkIfMatch(w http.ResponseWriter, r *http.Request, id any) bool {
+	if c.currentETag == nil {
+		return true
+	}
+
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		if c.requireIfMatch {
+			http.Error(w, "if-match header required", http.StatusPreconditionRequired)
+			return false
+		}
+		return true
+	}
+
+	tag, err := c.currentETag(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "resource not found", http.StatusNotFound)
+			return false
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+
+	if !matchETag(header, tag, false) {
+		http.Error(w, "precondition failed", http.StatusPreconditionFailed)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,59 @@
+package gocrud
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditional_MatchETag(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		tag    string
+		weak   bool
+		want   bool
+	}{
+		{name: "exact", header: `"abc"`, tag: `"abc"`, want: true},
+		{name: "list", header: `"xyz", "abc"`, tag: `"abc"`, want: true},
+		{name: "wildcard", header: `*`, tag: `"abc"`, want: true},
+		{name: "mismatch", header: `"xyz"`, tag: `"abc"`, want: false},
+		{name: "weak strong comparison", header: `W/"abc"`, tag: `"abc"`, want: false},
+		{name: "weak weak comparison", header: `W/"abc"`, tag: `"abc"`, weak: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchETag(tt.header, tt.tag, tt.weak))
+		})
+	}
+}
+
+func TestConditional_NotModified(t *testing.T) {
+	modified := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Test If-Modified-Since not modified", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("If-Modified-Since", modified.Format(http.TimeFormat))
+
+		assert.True(t, notModified(req, `"abc"`, modified))
+	})
+
+	t.Run("Test If-Modified-Since modified", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("If-Modified-Since", modified.Add(-time.Hour).Format(http.TimeFormat))
+
+		assert.False(t, notModified(req, `"abc"`, modified))
+	})
+
+	t.Run("Test If-None-Match takes precedence", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("If-None-Match", `"xyz"`)
+		req.Header.Set("If-Modified-Since", modified.Format(http.TimeFormat))
+
+		assert.False(t, notModified(req, `"abc"`, modified))
+	})
+}
@@ -0,0 +1,115 @@
+package gocrud
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+type converter struct {
+	encode func(v reflect.Value) (driver.Value, error)
+	decode func(src any, dst reflect.Value) error
+}
+
+type converters map[reflect.Type]converter
+
+var global = struct {
+	sync.RWMutex
+	converters converters
+}{converters: converters{}}
+
+func newConverter[T any](encode func(T) (driver.Value, error), decode func(src any) (T, error)) (reflect.Type, converter) {
+	return reflect.TypeFor[T](), converter{
+		encode: func(v reflect.Value) (driver.Value, error) {
+			t, ok := v.Interface().(T)
+			if !ok {
+				return nil, fmt.Errorf("converter for %s got %s", reflect.TypeFor[T](), v.Type())
+			}
+			return encode(t)
+		},
+		decode: func(src any, dst reflect.Value) error {
+			t, err := decode(src)
+			if err != nil {
+				return err
+			}
+			dst.Set(reflect.ValueOf(&t).Elem())
+			return nil
+		},
+	}
+}
+
+// RegisterConverter registers how fields of type T are written to and read from the database,
+// for every repository. decode receives the raw driver value, which is nil for NULL.
+func RegisterConverter[T any](encode func(T) (driver.Value, error), decode func(src any) (T, error)) {
+	t, c := newConverter(encode, decode)
+
+	global.Lock()
+	defer global.Unlock()
+
+	global.converters[t] = c
+}
+
+// WithConverter registers a converter for fields of type T used by this repository only.
+// It takes precedence over converters registered with RegisterConverter.
+func WithConverter[T any](encode func(T) (driver.Value, error), decode func(src any) (T, error)) Option {
+	return func(c *config) {
+		if c.converters == nil {
+			c.converters = converters{}
+		}
+
+		t, conv := newConverter(encode, decode)
+		c.converters[t] = conv
+	}
+}
+
+func globalConverter(t reflect.Type) (converter, bool) {
+	global.RLock()
+	defer global.RUnlock()
+
+	c, ok := global.converters[t]
+
+	return c, ok
+}
+
+// convertedColumn wraps a field whose type has a registered converter.
+type convertedColumn struct {
+	field     reflect.Value
+	converter converter
+}
+
+func (c convertedColumn) Scan(src any) error {
+	return c.converter.decode(src, c.field)
+}
+
+func (c convertedColumn) Value() (driver.Value, error) {
+	return c.converter.encode(c.field)
+}
+
+func (c convertedColumn) fieldPtr() any {
+	return c.field.Addr().Interface()
+}
+
+// convert wraps a plain field pointer, or a field converted with a global converter, using
+// the repository converter for its type, falling back to a global one.
+func (cs converters) convert(p any) any {
+	converted, isConverted := p.(convertedColumn)
+	if !isConverted && reflect.ValueOf(p).Kind() != reflect.Pointer {
+		return p
+	}
+
+	field := fieldValue(p)
+	if c, ok := cs[field.Type()]; ok {
+		return convertedColumn{field: field, converter: c}
+	}
+
+	if isConverted {
+		return converted
+	}
+
+	if c, ok := globalConverter(field.Type()); ok {
+		return convertedColumn{field: field, converter: c}
+	}
+
+	return p
+}
@@ -0,0 +1,125 @@
+package gocrud
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+type Status int
+
+const (
+	StatusActive Status = iota + 1
+	StatusRetired
+)
+
+func init() {
+	RegisterConverter(
+		func(m Money) (driver.Value, error) {
+			return fmt.Sprintf("%d %s", m.Cents, m.Currency), nil
+		},
+		func(src any) (Money, error) {
+			var m Money
+			if src == nil {
+				return m, nil
+			}
+			_, err := fmt.Sscanf(string(src.([]byte)), "%d %s", &m.Cents, &m.Currency)
+			return m, err
+		},
+	)
+}
+
+type ModelWithConverters struct {
+	ID     int    `json:"id"`
+	Price  Money  `json:"price"`
+	Status Status `json:"status"`
+	Reflection
+}
+
+func statusConverter() Option {
+	return WithConverter(
+		func(s Status) (driver.Value, error) {
+			return strings.ToLower([]string{"", "ACTIVE", "RETIRED"}[s]), nil
+		},
+		func(src any) (Status, error) {
+			switch string(src.([]byte)) {
+			case "active":
+				return StatusActive, nil
+			case "retired":
+				return StatusRetired, nil
+			default:
+				return 0, fmt.Errorf("unknown status %q", src)
+			}
+		},
+	)
+}
+
+func TestConverter_Repository(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewGenericRepository(db, "table_name", func() *ModelWithConverters { return &ModelWithConverters{} }, statusConverter())
+
+	t.Run("Test converters on Get()", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "price", "status"}).AddRow(1, []byte("1999 EUR"), []byte("retired"))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM table_name WHERE id = ? LIMIT 1")).WithArgs(1).WillReturnRows(rows)
+
+		got, err := repo.Get(context.Background(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, &ModelWithConverters{ID: 1, Price: Money{Cents: 1999, Currency: "EUR"}, Status: StatusRetired}, got)
+	})
+
+	t.Run("Test converters on Update()", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE table_name SET price = ?, status = ? WHERE id = ?")).
+			WithArgs("1999 EUR", "active", 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Update(context.Background(), &ModelWithConverters{Price: Money{Cents: 1999, Currency: "EUR"}, Status: StatusActive}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Test global converter on hand-written map", func(t *testing.T) {
+		m := &Money{}
+		got := converters{}.convert(m)
+
+		assert.IsType(t, convertedColumn{}, got)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestConverter_StructToMap(t *testing.T) {
+	i := &ModelWithConverters{Price: Money{Cents: 5, Currency: "USD"}}
+
+	got := i.StructToMap(i)
+
+	assert.IsType(t, convertedColumn{}, got["price"])
+	assert.Equal(t, &i.Status, got["status"])
+
+	v, err := got["price"].(driver.Valuer).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "5 USD", v)
+}
@@ -0,0 +1,416 @@
+package gocrud
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// columnDef is a table column derived from a model field.
+type columnDef struct {
+	Name     string
+	Type     string
+	Nullable bool
+	Serial   bool
+	Options  tagOptions
+}
+
+func (c columnDef) String() string {
+	if c.Serial {
+		return c.Name + " " + c.Type
+	}
+
+	def := c.Name + " " + c.Type
+	if !c.Nullable {
+		def += " NOT NULL"
+	}
+	if v, ok := c.Options["default"]; ok && v != "" {
+		def += " DEFAULT " + v
+	}
+	if v, ok := c.Options["unique"]; ok && v == "" {
+		def += " UNIQUE"
+	}
+
+	return def
+}
+
+// CreateTableSQL derives the CREATE TABLE statement for the repository's table from the
+// model, followed by CREATE INDEX statements. Column types follow the dialect and can be
+// overridden with the `type=` tag option. Pointer, sql.Null and nullzero fields are nullable,
+// and the options `default=expr`, `unique[=index]` and `index[=name]` add constraints and
+// indexes; fields sharing an index name form a composite index.
+func (r *Repository[M, K]) CreateTableSQL() ([]string, error) {
+	columns, err := r.modelColumns()
+	if err != nil {
+		return nil, err
+	}
+
+	defs := make([]string, 0, len(columns)+1)
+	for _, c := range columns {
+		defs = append(defs, "\t"+c.String())
+	}
+
+	if !slices.ContainsFunc(columns, func(c columnDef) bool { return c.Serial }) {
+		defs = append(defs, fmt.Sprintf("\tPRIMARY KEY (%s)", strings.Join(r.config.keyColumns, ", ")))
+	}
+
+	indexes := r.indexes(columns)
+	if r.config.dialect.InlineIndexes {
+		for _, i := range indexes {
+			defs = append(defs, fmt.Sprintf("\t%sINDEX %s (%s)", i.uniqueSQL(), i.name, strings.Join(i.columns, ", ")))
+		}
+		indexes = nil
+	}
+
+	statements := []string{fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n%s\n)", r.table, strings.Join(defs, ",\n"))}
+
+	return append(statements, r.indexSQL(indexes)...), nil
+}
+
+type indexDef struct {
+	name    string
+	unique  bool
+	columns []string
+}
+
+func (i *indexDef) uniqueSQL() string {
+	if i.unique {
+		return "UNIQUE "
+	}
+	return ""
+}
+
+// indexes collects the indexes declared by the `index` and `unique=name` options of columns.
+func (r *Repository[M, K]) indexes(columns []columnDef) []*indexDef {
+	var indexes []*indexDef
+	add := func(name string, unique bool, column string) {
+		for _, i := range indexes {
+			if i.name == name {
+				i.columns = append(i.columns, column)
+				return
+			}
+		}
+		indexes = append(indexes, &indexDef{name: name, unique: unique, columns: []string{column}})
+	}
+
+	for _, c := range columns {
+		if name, ok := c.Options["index"]; ok {
+			if name == "" {
+				name = fmt.Sprintf("%s_%s_idx", r.table, c.Name)
+			}
+			add(name, false, c.Name)
+		}
+		if name := c.Options["unique"]; name != "" {
+			add(name, true, c.Name)
+		}
+	}
+
+	return indexes
+}
+
+// indexSQL returns the CREATE INDEX statements of indexes.
+func (r *Repository[M, K]) indexSQL(indexes []*indexDef) []string {
+	ifNotExists := "IF NOT EXISTS "
+	if r.config.dialect.InlineIndexes {
+		ifNotExists = ""
+	}
+
+	statements := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		statements = append(statements, fmt.Sprintf("CREATE %sINDEX %s%s ON %s (%s)",
+			i.uniqueSQL(), ifNotExists, i.name, r.table, strings.Join(i.columns, ", ")))
+	}
+
+	return statements
+}
+
+// modelColumns derives the column definitions of the model, in struct field order.
+func (r *Repository[M, K]) modelColumns() ([]columnDef, error) {
+	model := r.getConcreteType()
+	fields := r.fields(model)
+	tags := structTags(model)
+	names := orderedColumns(model)
+
+	columns := make([]columnDef, 0, len(names)+1)
+	for _, name := range names {
+		options := tags[name].options
+		if options == nil {
+			options = tagOptions{}
+		}
+
+		typ, nullable, err := columnType(r.config.dialect, fields[name])
+		if v := options["type"]; v != "" {
+			typ, err = v, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", name, err)
+		}
+
+		c := columnDef{Name: name, Type: typ, Nullable: nullable, Options: options}
+		if slices.Contains(r.config.keyColumns, name) {
+			c.Nullable = false
+			if !r.composite() && r.config.idStrategy == DatabaseID && options["type"] == "" &&
+				(typ == r.config.dialect.Types.Int || typ == r.config.dialect.Types.BigInt) {
+				c.Type, c.Serial = r.config.dialect.Types.Serial, true
+			}
+		}
+		columns = append(columns, c)
+	}
+
+	if _, ok := fields[softDeleteColumn]; r.config.softDelete && !ok {
+		columns = append(columns, columnDef{Name: softDeleteColumn, Type: r.config.dialect.Types.Time, Nullable: true})
+	}
+
+	return columns, nil
+}
+
+// orderedColumns returns the columns mapped by the model in struct field order. Columns without
+// a struct field, e.g. from a hand-written StructToMap, go last.
+func orderedColumns(model Model) []string {
+	tags := structTags(model)
+	position := func(name string) int {
+		if t, ok := tags[name]; ok {
+			return t.index
+		}
+		return math.MaxInt
+	}
+
+	names := slices.Collect(maps.Keys(model.StructToMap(model)))
+	slices.SortFunc(names, func(a, b string) int {
+		if c := cmp.Compare(position(a), position(b)); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+
+	return names
+}
+
+type structTag struct {
+	index   int
+	options tagOptions
+}
+
+// structTags maps the columns of a model to the position and `db` tag options of their struct
+// field, matching StructToMap entries to fields by address so hand-written maps work too.
+func structTags(model Model) map[string]structTag {
+	tags := make(map[string]structTag)
+
+	val := reflect.Indirect(reflect.ValueOf(model))
+	if val.Kind() != reflect.Struct {
+		return tags
+	}
+
+	for name, p := range model.StructToMap(model) {
+		field := fieldValue(p)
+		if !field.CanAddr() {
+			continue
+		}
+
+		for i := range val.NumField() {
+			f := val.Field(i)
+			if f.Addr().Pointer() == field.Addr().Pointer() && f.Type() == field.Type() {
+				_, options := parseTag(val.Type().Field(i).Tag.Get("db"))
+				tags[name] = structTag{index: i, options: options}
+				break
+			}
+		}
+	}
+
+	return tags
+}
+
+// columnType maps a StructToMap entry to a column type of the dialect and its nullability.
+func columnType(d Dialect, p any) (string, bool, error) {
+	switch c := p.(type) {
+	case jsonColumn:
+		switch c.field.Kind() {
+		case reflect.Map, reflect.Slice, reflect.Pointer, reflect.Interface:
+			return d.Types.JSON, true, nil
+		default:
+			return d.Types.JSON, false, nil
+		}
+	case postgresArray:
+		typ, _, err := goColumnType(d, c.field.Type())
+		return typ, true, err
+	case nullZero:
+		typ, _, err := goColumnType(d, c.field.Type())
+		return typ, true, err
+	case convertedColumn:
+		v, err := c.Value()
+		if err != nil {
+			return "", false, err
+		}
+		return valueColumnType(d, v)
+	default:
+	}
+
+	v := reflect.ValueOf(p)
+	if v.Kind() != reflect.Pointer {
+		return "", false, fmt.Errorf("cannot derive a column type from %T; add a type= tag option", p)
+	}
+
+	return goColumnType(d, v.Type().Elem())
+}
+
+func goColumnType(d Dialect, t reflect.Type) (string, bool, error) {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t, nullable = t.Elem(), true
+	}
+
+	if t == reflect.TypeFor[time.Time]() {
+		return d.Types.Time, nullable, nil
+	}
+
+	// sql.Null* and Null[T] are structs holding the value next to a Valid flag.
+	if t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(reflect.TypeFor[sql.Scanner]()) {
+		for i := range t.NumField() {
+			if t.Field(i).Name != "Valid" {
+				typ, _, err := goColumnType(d, t.Field(i).Type)
+				return typ, true, err
+			}
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return d.Types.Int, nullable, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return d.Types.BigInt, nullable, nil
+	case reflect.Float32, reflect.Float64:
+		return d.Types.Float, nullable, nil
+	case reflect.Bool:
+		return d.Types.Bool, nullable, nil
+	case reflect.String:
+		return d.Types.Text, nullable, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return d.Types.Bytes, nullable, nil
+		}
+		if !d.Arrays {
+			return d.Types.JSON, true, nil
+		}
+		elem, _, err := goColumnType(d, t.Elem())
+		return elem + "[]", true, err
+	case reflect.Map, reflect.Struct:
+		return d.Types.JSON, nullable, nil
+	default:
+		return "", false, fmt.Errorf("cannot derive a column type from %s; add a type= tag option", t)
+	}
+}
+
+// valueColumnType maps the driver value produced by a converter to a column type.
+func valueColumnType(d Dialect, v driver.Value) (string, bool, error) {
+	switch v.(type) {
+	case nil:
+		return d.Types.Text, true, nil
+	case int64:
+		return d.Types.BigInt, false, nil
+	case float64:
+		return d.Types.Float, false, nil
+	case bool:
+		return d.Types.Bool, false, nil
+	case []byte:
+		return d.Types.Bytes, false, nil
+	case time.Time:
+		return d.Types.Time, false, nil
+	default:
+		return d.Types.Text, false, nil
+	}
+}
+
+// AutoMigrate creates the table from the model if it does not exist, otherwise adds the columns
+// of model fields missing from it along with their indexes. Existing columns are never altered
+// or dropped, which makes it suited to development and tests; use the migrate package for
+// production schemas. Added NOT NULL columns without a `default=` get the zero value of their
+// type as default, or become nullable if there is none.
+func (r *Repository[M, K]) AutoMigrate(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	statements, err := r.migrationSQL(ctx)
+	if err != nil || len(statements) == 0 {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repository[M, K]) migrationSQL(ctx context.Context) ([]string, error) {
+	existing, err := r.TableColumns(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return r.CreateTableSQL()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := r.modelColumns()
+	if err != nil {
+		return nil, err
+	}
+
+	var added []columnDef
+	for _, c := range columns {
+		if !slices.ContainsFunc(existing, func(e Column) bool { return e.Name == c.Name }) {
+			added = append(added, addableColumn(c, r.table))
+		}
+	}
+
+	statements := make([]string, 0, len(added))
+	for _, c := range added {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", r.table, c))
+	}
+
+	return append(statements, r.indexSQL(r.indexes(added))...), nil
+}
+
+// addableColumn adapts a column definition so it can be added to a table that may hold rows:
+// UNIQUE becomes a unique index and NOT NULL columns need a default.
+func addableColumn(c columnDef, table string) columnDef {
+	c.Options = maps.Clone(c.Options)
+	if name, ok := c.Options["unique"]; ok && name == "" {
+		c.Options["unique"] = fmt.Sprintf("%s_%s_key", table, c.Name)
+	}
+
+	if c.Nullable || c.Options["default"] != "" {
+		return c
+	}
+
+	switch sqlFamily(c.Type) {
+	case intFamily, floatFamily:
+		c.Options["default"] = "0"
+	case boolFamily:
+		c.Options["default"] = "false"
+	case textFamily:
+		c.Options["default"] = "''"
+	default:
+		c.Nullable = true
+	}
+
+	return c
+}
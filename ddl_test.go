@@ -0,0 +1,191 @@
+package gocrud
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type ModelWithIndexes struct {
+	ID        int       `json:"id"`
+	TenantID  int       `json:"tenant_id" db:"tenant_id,unique=devices_tenant_serial"`
+	Serial    string    `json:"serial" db:"serial,unique=devices_tenant_serial"`
+	Email     string    `json:"email" db:"email,unique"`
+	Name      string    `json:"name" db:"name,index,type=VARCHAR(64)"`
+	Active    bool      `json:"active" db:"active,default=true"`
+	CreatedAt time.Time `json:"created_at" db:"created_at,index=devices_created"`
+	Reflection
+}
+
+func TestDDL_CreateTableSQL(t *testing.T) {
+	t.Run("Test CreateTableSQL() serial key and indexes", func(t *testing.T) {
+		repo := NewGenericRepository(nil, "devices", func() *ModelWithIndexes { return &ModelWithIndexes{} },
+			WithDialect(Postgres), WithSoftDelete())
+
+		got, err := repo.CreateTableSQL()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{
+			"CREATE TABLE IF NOT EXISTS devices (\n" +
+				"\tid BIGSERIAL PRIMARY KEY,\n" +
+				"\ttenant_id BIGINT NOT NULL,\n" +
+				"\tserial TEXT NOT NULL,\n" +
+				"\temail TEXT NOT NULL UNIQUE,\n" +
+				"\tname VARCHAR(64) NOT NULL,\n" +
+				"\tactive BOOLEAN NOT NULL DEFAULT true,\n" +
+				"\tcreated_at TIMESTAMPTZ NOT NULL,\n" +
+				"\tdeleted_at TIMESTAMPTZ\n" +
+				")",
+			"CREATE UNIQUE INDEX IF NOT EXISTS devices_tenant_serial ON devices (tenant_id, serial)",
+			"CREATE INDEX IF NOT EXISTS devices_name_idx ON devices (name)",
+			"CREATE INDEX IF NOT EXISTS devices_created ON devices (created_at)",
+		}, got)
+	})
+
+	t.Run("Test CreateTableSQL() MySQL inline indexes", func(t *testing.T) {
+		repo := NewGenericRepository(nil, "devices", func() *ModelWithIndexes { return &ModelWithIndexes{} },
+			WithDialect(MySQL), WithSoftDelete())
+
+		got, err := repo.CreateTableSQL()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{
+			"CREATE TABLE IF NOT EXISTS devices (\n" +
+				"\tid BIGINT AUTO_INCREMENT PRIMARY KEY,\n" +
+				"\ttenant_id BIGINT NOT NULL,\n" +
+				"\tserial VARCHAR(255) NOT NULL,\n" +
+				"\temail VARCHAR(255) NOT NULL UNIQUE,\n" +
+				"\tname VARCHAR(64) NOT NULL,\n" +
+				"\tactive BOOLEAN NOT NULL DEFAULT true,\n" +
+				"\tcreated_at DATETIME(6) NOT NULL,\n" +
+				"\tdeleted_at DATETIME(6),\n" +
+				"\tUNIQUE INDEX devices_tenant_serial (tenant_id, serial),\n" +
+				"\tINDEX devices_name_idx (name),\n" +
+				"\tINDEX devices_created (created_at)\n" +
+				")",
+		}, got)
+	})
+
+	t.Run("Test CreateTableSQL() composite key", func(t *testing.T) {
+		repo := NewRepository[*Product, SKUKey](nil, "products", func() *Product { return &Product{} },
+			WithDialect(SQLite), WithPrimaryKey("tenant_id", "sku"))
+
+		got, err := repo.CreateTableSQL()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{
+			"CREATE TABLE IF NOT EXISTS products (\n" +
+				"\ttenant_id INTEGER NOT NULL,\n" +
+				"\tsku TEXT NOT NULL,\n" +
+				"\tname TEXT NOT NULL,\n" +
+				"\tPRIMARY KEY (tenant_id, sku)\n" +
+				")",
+		}, got)
+	})
+
+	t.Run("Test CreateTableSQL() nullable columns", func(t *testing.T) {
+		repo := NewGenericRepository(nil, "people", func() *ModelWithNulls { return &ModelWithNulls{} },
+			WithDialect(MySQL))
+
+		got, err := repo.CreateTableSQL()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []string{
+			"CREATE TABLE IF NOT EXISTS people (\n" +
+				"\tid BIGINT AUTO_INCREMENT PRIMARY KEY,\n" +
+				"\tnickname VARCHAR(255),\n" +
+				"\temail VARCHAR(255),\n" +
+				"\tphone VARCHAR(255),\n" +
+				"\tboard VARCHAR(255),\n" +
+				"\tscore BIGINT,\n" +
+				"\ttags BLOB\n" +
+				")",
+		}, got)
+	})
+
+	t.Run("Test CreateTableSQL() supplied string key", func(t *testing.T) {
+		repo := NewRepository[*ModelWithStringKey, string](nil, "items", func() *ModelWithStringKey { return &ModelWithStringKey{} },
+			WithIDStrategy(SuppliedID))
+
+		got, err := repo.CreateTableSQL()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Contains(t, got[0], "PRIMARY KEY (id)")
+		assert.NotContains(t, got[0], "IDENTITY")
+	})
+}
+
+func TestDDL_AutoMigrate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewGenericRepository(db, "devices", func() *ModelWithIndexes { return &ModelWithIndexes{} }, WithDialect(SQLite))
+	columns := []string{"name", "type", "not_null", "has_default"}
+	query := regexp.QuoteMeta(SQLite.ColumnsQuery)
+
+	t.Run("Test AutoMigrate() missing table", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs("devices").WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS devices (\n\tid INTEGER PRIMARY KEY AUTOINCREMENT,")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE UNIQUE INDEX IF NOT EXISTS devices_tenant_serial")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE INDEX IF NOT EXISTS devices_name_idx")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE INDEX IF NOT EXISTS devices_created")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.AutoMigrate(context.Background()))
+	})
+
+	t.Run("Test AutoMigrate() adds missing columns", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs("devices").WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("id", "INTEGER", false, true).
+			AddRow("tenant_id", "INTEGER", true, false).
+			AddRow("serial", "TEXT", true, false).
+			AddRow("name", "VARCHAR(64)", true, false))
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE devices ADD COLUMN email TEXT NOT NULL DEFAULT ''")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE devices ADD COLUMN active BOOLEAN NOT NULL DEFAULT true")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE devices ADD COLUMN created_at DATETIME")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE UNIQUE INDEX IF NOT EXISTS devices_email_key ON devices (email)")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE INDEX IF NOT EXISTS devices_created ON devices (created_at)")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.AutoMigrate(context.Background()))
+	})
+
+	t.Run("Test AutoMigrate() up to date", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns)
+		for _, name := range []string{"id", "tenant_id", "serial", "email", "name", "active", "created_at"} {
+			rows.AddRow(name, "TEXT", true, false)
+		}
+		mock.ExpectQuery(query).WithArgs("devices").WillReturnRows(rows)
+
+		assert.NoError(t, repo.AutoMigrate(context.Background()))
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
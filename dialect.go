@@ -0,0 +1,121 @@
+package gocrud
+
+import sq "github.com/Masterminds/squirrel"
+
+// Dialect describes the SQL flavour of the database behind a repository.
+type Dialect struct {
+	Name string
+	// Placeholder is the bind variable format, e.g. sq.Dollar for PostgreSQL.
+	Placeholder sq.PlaceholderFormat
+	// Returning reports whether INSERT ... RETURNING is supported.
+	Returning bool
+	// Arrays reports whether slice fields can be stored as native arrays. Otherwise they are stored as JSON.
+	Arrays bool
+	// InlineIndexes reports that CREATE INDEX IF NOT EXISTS is unavailable, so generated DDL
+	// declares indexes inside CREATE TABLE instead.
+	InlineIndexes bool
+	// BackslashEscapes reports that a backslash escapes the next character in string literals,
+	// as in MySQL's default mode.
+	BackslashEscapes bool
+	// ColumnsQuery lists the columns of the table passed as its only argument, as rows of
+	// (name, type, not null, has default).
+	ColumnsQuery string
+	// Types are the column types used when generating DDL.
+	Types ColumnTypes
+}
+
+// ColumnTypes names the SQL types that Go field types are mapped to in generated DDL.
+type ColumnTypes struct {
+	Int    string
+	BigInt string
+	Float  string
+	Bool   string
+	Text   string
+	Time   string
+	Bytes  string
+	JSON   string
+	// Serial is the full definition of a database-generated integer primary key column.
+	Serial string
+}
+
+const informationSchemaColumns = `SELECT column_name, data_type, is_nullable = 'NO', column_default IS NOT NULL
+FROM information_schema.columns WHERE table_name = ? ORDER BY ordinal_position`
+
+var (
+	// Default keeps "?" placeholders and does not assume RETURNING is available, so generated
+	// non-integer keys need a dialect that opts in.
+	Default = Dialect{
+		Name:         "default",
+		Placeholder:  sq.Question,
+		Returning:    false,
+		ColumnsQuery: informationSchemaColumns,
+		Types: ColumnTypes{
+			Int:    "INTEGER",
+			BigInt: "BIGINT",
+			Float:  "DOUBLE PRECISION",
+			Bool:   "BOOLEAN",
+			Text:   "TEXT",
+			Time:   "TIMESTAMP",
+			Bytes:  "BLOB",
+			JSON:   "TEXT",
+			Serial: "BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY",
+		},
+	}
+	Postgres = Dialect{
+		Name:        "postgres",
+		Placeholder: sq.Dollar,
+		Returning:   true,
+		Arrays:      true,
+		ColumnsQuery: `SELECT column_name, CASE WHEN data_type = 'ARRAY' THEN udt_name ELSE data_type END,
+is_nullable = 'NO', column_default IS NOT NULL OR is_identity = 'YES'
+FROM information_schema.columns WHERE table_name = $1 AND table_schema = current_schema() ORDER BY ordinal_position`,
+		Types: ColumnTypes{
+			Int:    "INTEGER",
+			BigInt: "BIGINT",
+			Float:  "DOUBLE PRECISION",
+			Bool:   "BOOLEAN",
+			Text:   "TEXT",
+			Time:   "TIMESTAMPTZ",
+			Bytes:  "BYTEA",
+			JSON:   "JSONB",
+			Serial: "BIGSERIAL PRIMARY KEY",
+		},
+	}
+	SQLite = Dialect{
+		Name:         "sqlite",
+		Placeholder:  sq.Question,
+		Returning:    true,
+		ColumnsQuery: `SELECT name, type, "notnull" = 1, dflt_value IS NOT NULL OR pk = 1 FROM pragma_table_info(?) ORDER BY cid`,
+		Types: ColumnTypes{
+			Int:    "INTEGER",
+			BigInt: "INTEGER",
+			Float:  "REAL",
+			Bool:   "BOOLEAN",
+			Text:   "TEXT",
+			Time:   "DATETIME",
+			Bytes:  "BLOB",
+			JSON:   "JSON",
+			Serial: "INTEGER PRIMARY KEY AUTOINCREMENT",
+		},
+	}
+	MySQL = Dialect{
+		Name:             "mysql",
+		Placeholder:      sq.Question,
+		Returning:        false,
+		InlineIndexes:    true,
+		BackslashEscapes: true,
+		ColumnsQuery: `SELECT column_name, data_type, is_nullable = 'NO', column_default IS NOT NULL OR extra LIKE '%auto_increment%'
+FROM information_schema.columns WHERE table_name = ? AND table_schema = DATABASE() ORDER BY ordinal_position`,
+		Types: ColumnTypes{
+			Int:    "INT",
+			BigInt: "BIGINT",
+			Float:  "DOUBLE",
+			Bool:   "BOOLEAN",
+			Text:   "VARCHAR(255)",
+			Time:   "DATETIME(6)",
+			Bytes:  "BLOB",
+			JSON:   "JSON",
+			Serial: "BIGINT AUTO_INCREMENT PRIMARY KEY",
+		},
+	}
+)
@@ -8,16 +8,16 @@ import (
 	gocrud "github.com/tender-barbarian/go-crud"
 )
 
-type genericRepo[M gocrud.Model] interface {
-	Create(ctx context.Context, model M) (int, error)
-	Get(ctx context.Context, id int) (M, error)
+type genericRepo[M gocrud.Model, K comparable] interface {
+	Create(ctx context.Context, model M) (K, error)
+	Get(ctx context.Context, id K) (M, error)
 	GetAll(ctx context.Context) ([]M, error)
-	Delete(ctx context.Context, id int) error
-	Update(ctx context.Context, model M, id int) error
+	Delete(ctx context.Context, id K) error
+	Update(ctx context.Context, model M, id K) error
 	GetTable() string
 }
 
-func RegisterGenericRoutes[M gocrud.Model](repo genericRepo[M], mux *http.ServeMux) *http.ServeMux {
+func RegisterGenericRoutes[M gocrud.Model, K comparable](repo genericRepo[M, K], mux *http.ServeMux) *http.ServeMux {
 	gocrud.RegisterCreate(fmt.Sprintf("POST /%s", repo.GetTable()), mux, repo.Create)
 	gocrud.RegisterGet(fmt.Sprintf("GET /%s/{id}", repo.GetTable()), mux, repo.Get)
 	gocrud.RegisterGetAll(fmt.Sprintf("GET /%s", repo.GetTable()), mux, repo.GetAll)
@@ -0,0 +1,314 @@
+package gocrud
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+	formatCSV    = "csv"
+)
+
+var formatTypes = map[string]string{
+	formatJSON:   "application/json",
+	formatNDJSON: "application/x-ndjson",
+	formatCSV:    "text/csv",
+}
+
+// listFormat is the negotiated encoding of a list response. CSV responses have one column per
+// column mapped by the model, in struct field order.
+type listFormat struct {
+	name    string
+	columns []string
+}
+
+// negotiateList picks the format of a list response from ?format= or the Accept header, JSON by
+// default. It returns false if the requested format is unsupported, or is CSV and Out does not
+// implement Model.
+func negotiateList[Out any](r *http.Request) (listFormat, bool) {
+	name, ok := requestedFormat(r)
+	if !ok || name != formatCSV {
+		return listFormat{name: name}, ok
+	}
+
+	out, _ := newOf[Out]()
+	model, ok := any(out).(Model)
+	if !ok {
+		return listFormat{}, false
+	}
+
+	return listFormat{name: name, columns: orderedColumns(model)}, true
+}
+
+// newOf allocates the value behind a pointer type T, reporting false for other types.
+func newOf[T any]() (T, bool) {
+	var zero T
+
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Pointer {
+		return zero, false
+	}
+	v, ok := reflect.New(t.Elem()).Interface().(T)
+
+	return v, ok
+}
+
+// requestedFormat returns the format named by ?format=, or else the supported type with the
+// highest q-value in the Accept header, earlier types winning ties. Types with q=0 are refused.
+func requestedFormat(r *http.Request) (string, bool) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		_, ok := formatTypes[format]
+		return format, ok
+	}
+
+	best, bestQ := formatJSON, 0.0
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= bestQ {
+			continue
+		}
+
+		if mediaType == "application/ndjson" {
+			best, bestQ = formatNDJSON, q
+		}
+		for format, contentType := range formatTypes {
+			if mediaType == contentType {
+				best, bestQ = format, q
+			}
+		}
+	}
+
+	return best, true
+}
+
+// listEncoder writes the items of a list response one at a time.
+type listEncoder interface {
+	encode(item any) error
+	end() error
+}
+
+// start writes the response headers and returns the encoder for the format.
+func (f listFormat) start(w http.ResponseWriter) listEncoder {
+	w.Header().Set("Content-Type", formatTypes[f.name])
+	w.WriteHeader(http.StatusOK)
+
+	switch f.name {
+	case formatNDJSON:
+		return ndjsonEncoder{json.NewEncoder(w)}
+	case formatCSV:
+		e := &csvEncoder{w: csv.NewWriter(w), columns: f.columns}
+		_ = e.w.Write(f.columns) // errors are reported by end
+		return e
+	default:
+		return &jsonArrayEncoder{w: w}
+	}
+}
+
+type jsonArrayEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonArrayEncoder) encode(item any) error {
+	body, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	sep := ","
+	if e.count == 0 {
+		sep = "["
+	}
+	e.count++
+
+	_, err = io.WriteString(e.w, sep+string(body))
+
+	return err
+}
+
+func (e *jsonArrayEncoder) end() error {
+	if e.count == 0 {
+		_, err := io.WriteString(e.w, "[]\n")
+		return err
+	}
+
+	_, err := io.WriteString(e.w, "]\n")
+
+	return err
+}
+
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e ndjsonEncoder) encode(item any) error {
+	return e.enc.Encode(item)
+}
+
+func (e ndjsonEncoder) end() error {
+	return nil
+}
+
+type csvEncoder struct {
+	w       *csv.Writer
+	columns []string
+}
+
+func (e *csvEncoder) encode(item any) error {
+	model, ok := item.(Model)
+	if !ok {
+		return fmt.Errorf("cannot encode %T as CSV", item)
+	}
+
+	fields := model.StructToMap(model)
+	record := make([]string, len(e.columns))
+	for i, column := range e.columns {
+		value, err := csvValue(fields[column])
+		if err != nil {
+			return fmt.Errorf("column %s: %w", column, err)
+		}
+		record[i] = value
+	}
+
+	return e.w.Write(record)
+}
+
+func (e *csvEncoder) end() error {
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+// csvValue formats a StructToMap entry as a CSV field: NULL as an empty string, times as
+// RFC 3339 and structs, maps and slices as JSON. Fields of types registered with
+// RegisterConverter are written as their encoded driver value; converters set with WithConverter
+// belong to a repository and do not apply. Text that a spreadsheet would evaluate as a formula is
+// escaped with csvEscape.
+func csvValue(p any) (string, error) {
+	if _, ok := p.(driver.Valuer); !ok {
+		v := fieldValue(p)
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return "", nil
+			}
+			v = v.Elem()
+		}
+		p = v.Interface()
+	}
+
+	if valuer, ok := p.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return "", err
+		}
+		p = value
+	}
+
+	switch v := p.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return csvEscape(v), nil
+	case []byte:
+		return csvEscape(string(v)), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	}
+
+	switch reflect.ValueOf(p).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		body, err := json.Marshal(p)
+		return string(body), err
+	case reflect.String:
+		return csvEscape(fmt.Sprint(p)), nil
+	default:
+		return fmt.Sprint(p), nil
+	}
+}
+
+// csvFormula reports whether s, ignoring leading single quotes, starts with a character that
+// makes spreadsheets evaluate a cell as a formula.
+func csvFormula(s string) bool {
+	s = strings.TrimLeft(s, "'")
+
+	return s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0]))
+}
+
+// csvEscape prefixes text that a spreadsheet would evaluate as a formula with a single quote.
+// Text already starting with quotes before such a character gets one more, so that csvUnescape
+// restores every value exactly.
+func csvEscape(s string) string {
+	if csvFormula(s) {
+		return "'" + s
+	}
+
+	return s
+}
+
+// csvUnescape reverses csvEscape.
+func csvUnescape(s string) string {
+	if strings.HasPrefix(s, "'") && csvFormula(s) {
+		return s[1:]
+	}
+
+	return s
+}
+
+// csvScan parses a CSV field written by csvValue into a StructToMap entry. An empty field is
+// NULL, or the zero value for columns that cannot hold NULL. Fields of types registered with
+// RegisterConverter are decoded by their converter, which receives the field as a string, or nil
+// for an empty field.
+func csvScan(p any, s string) error {
+	s = csvUnescape(s)
+
+	if scanner, ok := p.(sql.Scanner); ok {
+		if s == "" {
+			return scanner.Scan(nil)
+		}
+		return scanner.Scan(s)
+	}
+
+	v := fieldValue(p)
+	if s == "" {
+		v.SetZero()
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return json.Unmarshal([]byte(s), v.Addr().Interface())
+	default:
+		return assign(v, s)
+	}
+}
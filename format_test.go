@@ -0,0 +1,128 @@
+package gocrud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Reading struct {
+	ID       int            `json:"id"`
+	Sensor   string         `json:"sensor"`
+	Value    Null[float64]  `json:"value"`
+	Note     *string        `json:"note"`
+	Labels   map[string]any `json:"labels" db:",json"`
+	TakenAt  time.Time      `json:"taken_at" db:"taken_at"`
+	Internal string         `json:"-" db:"-"`
+	Reflection
+}
+
+func TestMethod_GetAllFormats(t *testing.T) {
+	note := "recalibrated, \"manually\""
+	readings := []*Reading{
+		{ID: 1, Sensor: "t1", Value: NewNull(21.5), Note: &note, Labels: map[string]any{"room": "lab"}, TakenAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)},
+		{ID: 2, Sensor: "t2", TakenAt: time.Date(2024, 5, 1, 12, 5, 0, 0, time.UTC)},
+	}
+
+	mux := http.NewServeMux()
+	RegisterGetAll("GET /readings", mux, func(context.Context) ([]*Reading, error) { return readings, nil })
+	RegisterGetAll("GET /names", mux, func(context.Context) ([]string, error) { return []string{"a"}, nil })
+
+	t.Run("Test generic method: GetAll() - CSV", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readings?format=csv", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "id,sensor,value,note,labels,taken_at\n"+
+			"1,t1,21.5,\"recalibrated, \"\"manually\"\"\",\"{\"\"room\"\":\"\"lab\"\"}\",2024-05-01T12:00:00Z\n"+
+			"2,t2,,,,2024-05-01T12:05:00Z\n", rec.Body.String())
+	})
+
+	t.Run("Test generic method: GetAll() - NDJSON", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/readings", nil)
+		req.Header.Set("Accept", "text/html, application/x-ndjson;q=0.9")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+		assert.Equal(t, `{"id":1,"sensor":"t1","value":21.5,"note":"recalibrated, \"manually\"","labels":{"room":"lab"},"taken_at":"2024-05-01T12:00:00Z"}`+"\n"+
+			`{"id":2,"sensor":"t2","value":null,"note":null,"labels":null,"taken_at":"2024-05-01T12:05:00Z"}`+"\n", rec.Body.String())
+	})
+
+	t.Run("Test generic method: GetAll() - CSV via Accept", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/readings", nil)
+		req.Header.Set("Accept", "text/csv")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("Test generic method: GetAll() - Accept q-values", func(t *testing.T) {
+		for accept, want := range map[string]string{
+			"text/csv;q=0, application/json":                  "application/json",
+			"application/json;q=0.5, text/csv":                "text/csv",
+			"text/csv;q=0.2, application/x-ndjson;q=0.8":      "application/x-ndjson",
+			"application/x-ndjson;q=0.5, text/csv;q=0.5, */*": "application/x-ndjson",
+		} {
+			req := httptest.NewRequest(http.MethodGet, "/readings", nil)
+			req.Header.Set("Accept", accept)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, want, rec.Header().Get("Content-Type"), accept)
+		}
+	})
+
+	t.Run("Test generic method: GetAll() - CSV of a non-model", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/names?format=csv", nil))
+
+		assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+		assert.Equal(t, "unsupported format\n", rec.Body.String())
+	})
+}
+
+func TestFormat_CSVFormulas(t *testing.T) {
+	for _, tc := range []struct {
+		value string
+		want  string
+	}{
+		{"=HYPERLINK(\"http://x\")", "'=HYPERLINK(\"http://x\")"},
+		{"+1", "'+1"},
+		{"-1", "'-1"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"'=1", "''=1"},
+		{"'quoted", "'quoted"},
+		{"plain", "plain"},
+	} {
+		t.Run("Test CSV formula escaping: "+tc.value, func(t *testing.T) {
+			got, err := csvValue(&tc.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.want, got)
+
+			var back string
+			if err := csvScan(&back, got); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.value, back)
+		})
+	}
+
+	t.Run("Test CSV formula escaping: numbers are not escaped", func(t *testing.T) {
+		n := -1.5
+		got, err := csvValue(&n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "-1.5", got)
+	})
+}
@@ -0,0 +1,366 @@
+package gocrud
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"log"
+	"mime"
+	"net/http"
+	"slices"
+)
+
+const (
+	defaultBatchSize     = 100
+	defaultMaxUploadSize = 32 << 20
+)
+
+// Validator lets a model reject invalid input before it is stored by RegisterImport.
+type Validator interface {
+	Validate() error
+}
+
+// BatchError reports the model of a batch whose insert failed.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("record %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// CreateMany inserts the models in a single transaction and returns their keys in order. If an
+// insert fails nothing is stored, and the error is a *BatchError holding the model's index.
+//
+// Each model is inserted with its own INSERT statement, so that generated keys can be read back
+// one by one; a batch of n models costs n round trips to the database.
+func (r *Repository[M, K]) CreateMany(ctx context.Context, models []M) ([]K, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	ids := make([]K, 0, len(models))
+	for i, model := range models {
+		id, err := r.create(ctx, tx, model)
+		if err != nil {
+			return nil, &BatchError{Index: i, Err: err}
+		}
+		ids = append(ids, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// ImportReport is the response of RegisterImport.
+type ImportReport[K any] struct {
+	Created []K           `json:"created"`
+	Errors  []ImportError `json:"errors"`
+}
+
+// ImportError is a record of an upload that was not imported. Line is the line of the record in
+// the uploaded file.
+type ImportError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// WithBestEffort makes RegisterImport store every valid record, skipping the ones that fail,
+// instead of importing nothing unless all records succeed.
+func WithBestEffort() HandlerOption {
+	return func(c *handlerConfig) {
+		c.bestEffort = true
+	}
+}
+
+// WithBatchSize sets the number of records RegisterImport passes to f at once in best-effort
+// mode, 100 by default.
+func WithBatchSize(n int) HandlerOption {
+	return func(c *handlerConfig) {
+		c.batchSize = n
+	}
+}
+
+// WithMaxUploadSize sets the largest body in bytes that RegisterImport reads, 32 MiB by default.
+// Larger uploads get a 413 response.
+func WithMaxUploadSize(n int64) HandlerOption {
+	return func(c *handlerConfig) {
+		c.maxUploadSize = n
+	}
+}
+
+// RegisterImport accepts a CSV or NDJSON upload, selected by ?format= or the Content-Type, and
+// stores its records with f, typically Repository.CreateMany. CSV uploads start with a header
+// naming the mapped columns; fields are read like the CSV output of RegisterGetAll. Models
+// implementing Validator are validated.
+//
+// By default the import is all-or-nothing: f is called once with every record, and only if all
+// of them decode and validate. WithBestEffort instead calls f with batches of records and skips
+// the records that fail. The response is an ImportReport listing the created keys and the
+// failed records, with status 201 if there are no errors, 422 if nothing was created and 200
+// otherwise. An upload over the WithMaxUploadSize limit gets a 413, though in best-effort mode
+// the batches stored before the limit was reached are kept.
+func RegisterImport[M Model, K any](pattern string, mux *http.ServeMux, f func(context.Context, []M) ([]K, error), opts ...HandlerOption) {
+	c := newHandlerConfig(opts)
+	if c.batchSize <= 0 {
+		c.batchSize = defaultBatchSize
+	}
+	if c.maxUploadSize <= 0 {
+		c.maxUploadSize = defaultMaxUploadSize
+	}
+
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		format, ok := uploadFormat(r)
+		if !ok {
+			http.Error(w, "unsupported format", http.StatusUnsupportedMediaType)
+			return
+		}
+
+		report := ImportReport[K]{Created: []K{}, Errors: []ImportError{}}
+		fail := func(line int, err error) {
+			report.Errors = append(report.Errors, ImportError{Line: line, Error: err.Error()})
+		}
+
+		var batch []importRecord[M]
+		body := http.MaxBytesReader(w, r.Body, c.maxUploadSize)
+		for rec, err := range decodeUpload[M](body, format) {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "upload too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if rec.err == nil {
+				if v, ok := any(rec.model).(Validator); ok {
+					rec.err = v.Validate()
+				}
+			}
+			if rec.err != nil {
+				fail(rec.line, rec.err)
+				continue
+			}
+
+			batch = append(batch, rec)
+			if c.bestEffort && len(batch) == c.batchSize {
+				report.Created = append(report.Created, importBatch(r.Context(), f, batch, fail)...)
+				batch = batch[:0]
+			}
+		}
+
+		switch {
+		case c.bestEffort:
+			report.Created = append(report.Created, importBatch(r.Context(), f, batch, fail)...)
+		case len(report.Errors) == 0 && len(batch) > 0:
+			ids, err := f(r.Context(), importModels(batch))
+			var batchErr *BatchError
+			switch {
+			case errors.As(err, &batchErr) && batchErr.Index < len(batch):
+				fail(batch[batchErr.Index].line, batchErr.Err)
+			case err != nil:
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			default:
+				report.Created = ids
+			}
+		}
+
+		slices.SortStableFunc(report.Errors, func(a, b ImportError) int {
+			return cmp.Compare(a.Line, b.Line)
+		})
+
+		status := http.StatusOK
+		switch {
+		case len(report.Errors) == 0:
+			status = http.StatusCreated
+		case len(report.Created) == 0:
+			status = http.StatusUnprocessableEntity
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			log.Printf("failed to encode import report: %v", err)
+		}
+	})
+}
+
+// importBatch stores a batch with f. When a record fails, it is reported and the rest of the
+// batch is retried without it.
+func importBatch[M Model, K any](ctx context.Context, f func(context.Context, []M) ([]K, error), batch []importRecord[M], fail func(int, error)) []K {
+	for len(batch) > 0 {
+		ids, err := f(ctx, importModels(batch))
+		if err == nil {
+			return ids
+		}
+
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) || batchErr.Index >= len(batch) {
+			for _, rec := range batch {
+				fail(rec.line, err)
+			}
+			return nil
+		}
+
+		fail(batch[batchErr.Index].line, batchErr.Err)
+		batch = append(batch[:batchErr.Index:batchErr.Index], batch[batchErr.Index+1:]...)
+	}
+
+	return nil
+}
+
+type importRecord[M Model] struct {
+	line  int
+	model M
+	err   error
+}
+
+func importModels[M Model](batch []importRecord[M]) []M {
+	models := make([]M, len(batch))
+	for i, rec := range batch {
+		models[i] = rec.model
+	}
+
+	return models
+}
+
+func uploadFormat(r *http.Request) (string, bool) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format, format == formatCSV || format == formatNDJSON
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case formatTypes[formatCSV]:
+		return formatCSV, true
+	case formatTypes[formatNDJSON], "application/ndjson":
+		return formatNDJSON, true
+	default:
+		return "", false
+	}
+}
+
+// decodeUpload yields the records of an upload. Records that cannot be decoded carry their
+// error; an error that prevents reading further ends the iteration.
+func decodeUpload[M Model](body io.Reader, format string) iter.Seq2[importRecord[M], error] {
+	return func(yield func(importRecord[M], error) bool) {
+		if _, ok := newOf[M](); !ok {
+			var zero M
+			yield(importRecord[M]{}, fmt.Errorf("cannot import into %T", zero))
+			return
+		}
+
+		if format == formatCSV {
+			decodeCSV(body, yield)
+			return
+		}
+		decodeNDJSON(body, yield)
+	}
+}
+
+func decodeNDJSON[M Model](body io.Reader, yield func(importRecord[M], error) bool) {
+	reader := bufio.NewReader(body)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			yield(importRecord[M]{}, err)
+			return
+		}
+
+		if len(bytes.TrimSpace(data)) > 0 {
+			model, _ := newOf[M]()
+			rec := importRecord[M]{line: line, model: model}
+			if json.Unmarshal(data, model) != nil {
+				rec.err = errors.New("invalid json")
+			}
+			if !yield(rec, nil) {
+				return
+			}
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+func decodeCSV[M Model](body io.Reader, yield func(importRecord[M], error) bool) {
+	reader := csv.NewReader(body)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return
+	}
+	if err != nil {
+		yield(importRecord[M]{}, err)
+		return
+	}
+
+	model, _ := newOf[M]()
+	mapped := model.StructToMap(model)
+	columns := make([]string, len(header))
+	for i, column := range header {
+		if _, ok := mapped[column]; !ok {
+			yield(importRecord[M]{}, fmt.Errorf("unknown column %q", column))
+			return
+		}
+		columns[i] = column
+	}
+
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			yield(importRecord[M]{}, err)
+			return
+		}
+
+		model, _ := newOf[M]()
+		rec := importRecord[M]{model: model, err: err}
+		if parseErr != nil {
+			rec.line = parseErr.StartLine
+		} else {
+			rec.line, _ = reader.FieldPos(0)
+			m := model.StructToMap(model)
+			for i, column := range columns {
+				if err := csvScan(m[column], fields[i]); err != nil {
+					rec.err = fmt.Errorf("column %s: %w", column, err)
+					break
+				}
+			}
+		}
+
+		if !yield(rec, nil) {
+			return
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package gocrud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type Sensor struct {
+	ID    int            `json:"id"`
+	Name  string         `json:"name"`
+	Scale Null[float64]  `json:"scale"`
+	Tags  map[string]any `json:"tags" db:",json"`
+	Reflection
+}
+
+func (s *Sensor) Validate() error {
+	if s.Name == "" {
+		return errors.New("name is required")
+	}
+
+	return nil
+}
+
+func TestRepository_CreateMany(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewGenericRepository(db, "devices", func() *ModelWithReflection { return &ModelWithReflection{} })
+	insert := regexp.QuoteMeta("INSERT INTO devices")
+
+	t.Run("Test CreateMany()", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectCommit()
+
+		ids, err := repo.CreateMany(context.Background(), []*ModelWithReflection{{Name: "a"}, {Name: "b"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []int{1, 2}, ids)
+	})
+
+	t.Run("Test CreateMany() failure", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insert).WillReturnError(errors.New("duplicate key"))
+		mock.ExpectRollback()
+
+		_, err := repo.CreateMany(context.Background(), []*ModelWithReflection{{Name: "a"}, {Name: "a"}})
+
+		var batchErr *BatchError
+		assert.ErrorAs(t, err, &batchErr)
+		assert.Equal(t, 1, batchErr.Index)
+		assert.EqualError(t, batchErr.Err, "duplicate key")
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+// sensorStore fakes CreateMany, failing the inserts of sensors named in reject.
+type sensorStore struct {
+	calls  [][]string
+	reject map[string]bool
+	nextID int
+}
+
+func (s *sensorStore) CreateMany(_ context.Context, sensors []*Sensor) ([]int, error) {
+	names := make([]string, len(sensors))
+	for i, sensor := range sensors {
+		names[i] = sensor.Name
+	}
+	s.calls = append(s.calls, names)
+
+	ids := make([]int, 0, len(sensors))
+	for i, sensor := range sensors {
+		if s.reject[sensor.Name] {
+			return nil, &BatchError{Index: i, Err: errors.New("duplicate key")}
+		}
+		ids = append(ids, s.nextID+i+1)
+	}
+	s.nextID += len(ids)
+
+	return ids, nil
+}
+
+func upload(mux *http.ServeMux, target, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestMethod_Import(t *testing.T) {
+	t.Run("Test generic method: Import() - CSV", func(t *testing.T) {
+		store := &sensorStore{}
+		mux := http.NewServeMux()
+		RegisterImport("POST /sensors/import", mux, store.CreateMany)
+
+		rec := upload(mux, "/sensors/import", "text/csv; charset=utf-8",
+			"name,scale,tags\n"+
+				"t1,0.5,\"{\"\"room\"\":\"\"lab\"\"}\"\n"+
+				"t2,,\n")
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.JSONEq(t, `{"created":[1,2],"errors":[]}`, rec.Body.String())
+		assert.Equal(t, [][]string{{"t1", "t2"}}, store.calls)
+	})
+
+	t.Run("Test generic method: Import() - all-or-nothing", func(t *testing.T) {
+		store := &sensorStore{}
+		mux := http.NewServeMux()
+		RegisterImport("POST /sensors/import", mux, store.CreateMany)
+
+		rec := upload(mux, "/sensors/import", "application/x-ndjson",
+			`{"name":"t1"}`+"\n"+
+				`{"name":`+"\n"+
+				"\n"+
+				`{"scale":2}`+"\n")
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.JSONEq(t, `{"created":[],"errors":[{"line":2,"error":"invalid json"},{"line":4,"error":"name is required"}]}`, rec.Body.String())
+		assert.Empty(t, store.calls)
+	})
+
+	t.Run("Test generic method: Import() - all-or-nothing insert failure", func(t *testing.T) {
+		store := &sensorStore{reject: map[string]bool{"t2": true}}
+		mux := http.NewServeMux()
+		RegisterImport("POST /sensors/import", mux, store.CreateMany)
+
+		rec := upload(mux, "/sensors/import?format=csv", "", "name\nt1\nt2\nt3\n")
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.JSONEq(t, `{"created":[],"errors":[{"line":3,"error":"duplicate key"}]}`, rec.Body.String())
+	})
+
+	t.Run("Test generic method: Import() - best effort", func(t *testing.T) {
+		store := &sensorStore{reject: map[string]bool{"t2": true}}
+		mux := http.NewServeMux()
+		RegisterImport("POST /sensors/import", mux, store.CreateMany, WithBestEffort(), WithBatchSize(2))
+
+		rec := upload(mux, "/sensors/import", "text/csv", "name,scale\nt1,1\nt2,2\n,3\nt3,x\nt4,4\nt5,5\n")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"created":[1,2,3],"errors":[`+
+			`{"line":3,"error":"duplicate key"},`+
+			`{"line":4,"error":"name is required"},`+
+			`{"line":5,"error":"column scale: converting driver.Value type string (\"x\") to a float64: invalid syntax"}]}`, rec.Body.String())
+		assert.Equal(t, [][]string{{"t1", "t2"}, {"t1"}, {"t4", "t5"}}, store.calls)
+	})
+
+	t.Run("Test generic method: Import() - unknown column", func(t *testing.T) {
+		mux := http.NewServeMux()
+		RegisterImport("POST /sensors/import", mux, (&sensorStore{}).CreateMany)
+
+		rec := upload(mux, "/sensors/import", "text/csv", "name,color\nt1,red\n")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "unknown column \"color\"\n", rec.Body.String())
+	})
+
+	t.Run("Test generic method: Import() - upload too large", func(t *testing.T) {
+		store := &sensorStore{}
+		mux := http.NewServeMux()
+		RegisterImport("POST /sensors/import", mux, store.CreateMany, WithMaxUploadSize(16))
+
+		rec := upload(mux, "/sensors/import", "application/x-ndjson", `{"name":"t1"}`+"\n"+`{"name":"t2"}`+"\n")
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Equal(t, "upload too large\n", rec.Body.String())
+		assert.Empty(t, store.calls)
+	})
+
+	t.Run("Test generic method: Import() - unsupported format", func(t *testing.T) {
+		mux := http.NewServeMux()
+		RegisterImport("POST /sensors/import", mux, (&sensorStore{}).CreateMany)
+
+		rec := upload(mux, "/sensors/import", "application/json", `[{"name":"t1"}]`)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	})
+}
@@ -0,0 +1,59 @@
+package gocrud
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// jsonColumn wraps a field tagged `db:",json"` so it is stored as a JSON document.
+type jsonColumn struct {
+	field reflect.Value
+}
+
+func (j jsonColumn) Scan(src any) error {
+	var data []byte
+
+	switch v := src.(type) {
+	case nil:
+		j.field.SetZero()
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into JSON column of type %s", src, j.field.Type())
+	}
+
+	return json.Unmarshal(data, j.field.Addr().Interface())
+}
+
+func (j jsonColumn) Value() (driver.Value, error) {
+	switch j.field.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Pointer, reflect.Interface:
+		if j.field.IsNil() {
+			return nil, nil
+		}
+	default:
+	}
+
+	data, err := json.Marshal(j.field.Interface())
+	if err != nil {
+		return nil, err
+	}
+
+	// Sent as text, since some drivers encode []byte as a binary string rather than JSON.
+	return string(data), nil
+}
+
+func (j jsonColumn) fieldPtr() any {
+	return j.field.Addr().Interface()
+}
+
+// JSONColumn stores the field pointed to by ptr as a JSON document. It is the hand-written
+// equivalent of the `db:",json"` tag, e.g. for StructToMap implementations.
+func JSONColumn(ptr any) any {
+	return jsonColumn{field: reflect.ValueOf(ptr).Elem()}
+}
@@ -0,0 +1,62 @@
+package gocrud
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type settings struct {
+	Theme string `json:"theme"`
+	Beta  bool   `json:"beta"`
+}
+
+func TestJSON_Column(t *testing.T) {
+	t.Run("Test JSON column value", func(t *testing.T) {
+		s := settings{Theme: "dark", Beta: true}
+		got, err := jsonColumn{field: reflect.ValueOf(&s).Elem()}.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, `{"theme":"dark","beta":true}`, got)
+	})
+
+	t.Run("Test JSON column nil map value", func(t *testing.T) {
+		var m map[string]int
+		got, err := jsonColumn{field: reflect.ValueOf(&m).Elem()}.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Nil(t, got)
+	})
+
+	t.Run("Test JSON column scan", func(t *testing.T) {
+		var s settings
+		err := jsonColumn{field: reflect.ValueOf(&s).Elem()}.Scan([]byte(`{"theme":"dark","beta":true}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, settings{Theme: "dark", Beta: true}, s)
+	})
+
+	t.Run("Test JSON column scan NULL", func(t *testing.T) {
+		s := []string{"a"}
+		err := jsonColumn{field: reflect.ValueOf(&s).Elem()}.Scan(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Nil(t, s)
+	})
+
+	t.Run("Test JSON column scan invalid type", func(t *testing.T) {
+		var s settings
+		err := jsonColumn{field: reflect.ValueOf(&s).Elem()}.Scan(int64(1))
+
+		assert.Error(t, err)
+	})
+}
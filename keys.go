@@ -0,0 +1,101 @@
+package gocrud
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// ParseKey converts a path parameter into a key of type K. It supports string and integer
+// kinds and any type implementing encoding.TextUnmarshaler, such as most UUID types.
+func ParseKey[K any](s string) (K, error) {
+	var key K
+
+	err := parseInto(&key, s)
+
+	return key, err
+}
+
+func parseInto(dst any, s string) error {
+	if u, ok := dst.(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("unsupported key type %T", dst)
+	}
+	v = v.Elem()
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	default:
+		return fmt.Errorf("unsupported key type %T", dst)
+	}
+
+	return nil
+}
+
+// WithKeyParser replaces ParseKey for converting the {id} path parameter into a key.
+func WithKeyParser[K any](parse func(string) (K, error)) HandlerOption {
+	return WithRequestKeyParser(func(r *http.Request) (K, error) {
+		return parse(r.PathValue("id"))
+	})
+}
+
+// WithRequestKeyParser builds the key from the whole request, e.g. from several path parameters.
+func WithRequestKeyParser[K any](parse func(*http.Request) (K, error)) HandlerOption {
+	return func(c *handlerConfig) {
+		c.parseKey = func(r *http.Request) (any, error) {
+			return parse(r)
+		}
+	}
+}
+
+// pathKey extracts the key from the request. Composite keys (types implementing Model) are
+// read field by field from path parameters named after their columns, e.g. /{tenant_id}/{sku}.
+func pathKey[K any](r *http.Request, c *handlerConfig) (K, error) {
+	var key K
+
+	if c.parseKey != nil {
+		v, err := c.parseKey(r)
+		if err != nil {
+			return key, err
+		}
+
+		key, ok := v.(K)
+		if !ok {
+			return key, fmt.Errorf("key parser returned %T, want %T", v, key)
+		}
+
+		return key, nil
+	}
+
+	composite, ok := any(&key).(Model)
+	if !ok {
+		return ParseKey[K](r.PathValue("id"))
+	}
+
+	for column, dst := range composite.StructToMap(composite) {
+		if err := parseInto(dst, r.PathValue(column)); err != nil {
+			return key, fmt.Errorf("%s: %w", column, err)
+		}
+	}
+
+	return key, nil
+}
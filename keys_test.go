@@ -0,0 +1,120 @@
+package gocrud
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeys_ParseKey(t *testing.T) {
+	t.Run("Test ParseKey int", func(t *testing.T) {
+		got, err := ParseKey[int]("42")
+		assert.NoError(t, err)
+		assert.Equal(t, 42, got)
+	})
+
+	t.Run("Test ParseKey int64", func(t *testing.T) {
+		got, err := ParseKey[int64]("1790478563172503552")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1790478563172503552), got)
+	})
+
+	t.Run("Test ParseKey small integers", func(t *testing.T) {
+		i8, err := ParseKey[int8]("-12")
+		assert.NoError(t, err)
+		assert.Equal(t, int8(-12), i8)
+
+		i16, err := ParseKey[int16]("300")
+		assert.NoError(t, err)
+		assert.Equal(t, int16(300), i16)
+
+		u8, err := ParseKey[uint8]("255")
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(255), u8)
+
+		u16, err := ParseKey[uint16]("65535")
+		assert.NoError(t, err)
+		assert.Equal(t, uint16(65535), u16)
+	})
+
+	t.Run("Test ParseKey named integer", func(t *testing.T) {
+		type deviceID int64
+
+		got, err := ParseKey[deviceID]("7")
+		assert.NoError(t, err)
+		assert.Equal(t, deviceID(7), got)
+	})
+
+	t.Run("Test ParseKey out of range", func(t *testing.T) {
+		_, err := ParseKey[uint8]("256")
+		assert.Error(t, err)
+
+		_, err = ParseKey[int16]("-40000")
+		assert.Error(t, err)
+	})
+
+	t.Run("Test ParseKey string", func(t *testing.T) {
+		got, err := ParseKey[string]("d7e949b8-5c41-4972-b484-9c33b89af32c")
+		assert.NoError(t, err)
+		assert.Equal(t, "d7e949b8-5c41-4972-b484-9c33b89af32c", got)
+	})
+
+	t.Run("Test ParseKey TextUnmarshaler", func(t *testing.T) {
+		got, err := ParseKey[netip.Addr]("10.0.0.1")
+		assert.NoError(t, err)
+		assert.Equal(t, netip.MustParseAddr("10.0.0.1"), got)
+	})
+
+	t.Run("Test ParseKey invalid int", func(t *testing.T) {
+		_, err := ParseKey[int]("asd")
+		assert.Error(t, err)
+	})
+
+	t.Run("Test ParseKey unsupported type", func(t *testing.T) {
+		_, err := ParseKey[float64]("1.5")
+		assert.Error(t, err)
+	})
+}
+
+type tenantSKU struct {
+	TenantID int    `db:"tenant_id"`
+	SKU      string `db:"sku"`
+	Reflection
+}
+
+func TestKeys_PathKey(t *testing.T) {
+	t.Run("Test pathKey composite", func(t *testing.T) {
+		mux := http.NewServeMux()
+		var got tenantSKU
+		mux.HandleFunc("GET /products/{tenant_id}/{sku}", func(_ http.ResponseWriter, r *http.Request) {
+			key, err := pathKey[tenantSKU](r, &handlerConfig{})
+			assert.NoError(t, err)
+			got = key
+		})
+
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/products/7/AB-1", nil))
+
+		assert.Equal(t, tenantSKU{TenantID: 7, SKU: "AB-1"}, got)
+	})
+
+	t.Run("Test pathKey request parser", func(t *testing.T) {
+		mux := http.NewServeMux()
+		var got tenantSKU
+		c := newHandlerConfig([]HandlerOption{WithRequestKeyParser(func(r *http.Request) (tenantSKU, error) {
+			id, err := ParseKey[int](r.PathValue("tenant"))
+			return tenantSKU{TenantID: id, SKU: r.PathValue("sku")}, err
+		})})
+		mux.HandleFunc("GET /products/{tenant}/{sku}", func(_ http.ResponseWriter, r *http.Request) {
+			key, err := pathKey[tenantSKU](r, c)
+			assert.NoError(t, err)
+			got = key
+		})
+
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/products/7/AB-1", nil))
+
+		assert.Equal(t, tenantSKU{TenantID: 7, SKU: "AB-1"}, got)
+	})
+}
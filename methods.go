@@ -7,10 +7,12 @@ import (
 	"errors"
 	"log"
 	"net/http"
-	"strconv"
+	"time"
 )
 
-func RegisterCreate[In Model](pattern string, mux *http.ServeMux, f func(context.Context, In) (int, error)) {
+// RegisterCreate responds with {"id": ...}, or with the created object when f returns a model,
+// e.g. Repository.CreateReturning.
+func RegisterCreate[In Model, Out any](pattern string, mux *http.ServeMux, f func(context.Context, In) (Out, error)) {
 	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
 		var in In
 
@@ -26,9 +28,14 @@ func RegisterCreate[In Model](pattern string, mux *http.ServeMux, f func(context
 			return
 		}
 
+		var body any = map[string]interface{}{"id": out}
+		if _, ok := any(out).(Model); ok {
+			body = out
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusCreated)
-		err = json.NewEncoder(w).Encode(map[string]interface{}{"id": out})
+		err = json.NewEncoder(w).Encode(body)
 		if err != nil {
 			log.Printf("failed to encode created note: %v", err)
 			return
@@ -36,9 +43,11 @@ func RegisterCreate[In Model](pattern string, mux *http.ServeMux, f func(context
 	})
 }
 
-func RegisterGet[Out Model](pattern string, mux *http.ServeMux, f func(ctx context.Context, id int) (Out, error)) {
+func RegisterGet[Out Model, K any](pattern string, mux *http.ServeMux, f func(ctx context.Context, id K) (Out, error), opts ...HandlerOption) {
+	c := newHandlerConfig(opts)
+
 	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
-		id, err := strconv.Atoi(r.PathValue("id"))
+		id, err := pathKey[K](r, c)
 		if err != nil {
 			http.Error(w, "invalid param", http.StatusBadRequest)
 			return
@@ -54,18 +63,54 @@ func RegisterGet[Out Model](pattern string, mux *http.ServeMux, f func(ctx conte
 			return
 		}
 
+		tag, body, err := entityTag(out)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var modified time.Time
+		if m, ok := any(out).(LastModifier); ok {
+			modified = m.LastModified()
+		}
+
+		w.Header().Set("ETag", tag)
+		if !modified.IsZero() {
+			w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+		}
+
+		if notModified(r, tag, modified) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusCreated)
-		err = json.NewEncoder(w).Encode(out)
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write(append(body, '\n'))
 		if err != nil {
-			log.Printf("failed to encode created note: %v", err)
+			log.Printf("failed to write resource: %v", err)
 			return
 		}
 	})
 }
 
-func RegisterGetAll[Out any](pattern string, mux *http.ServeMux, f func(context.Context) ([]Out, error)) {
+// RegisterGetAll responds with a JSON array, or with NDJSON or CSV when requested with
+// ?format=ndjson|csv or an Accept header of application/x-ndjson or text/csv. CSV needs Out to
+// be a model; its header lists the mapped columns.
+func RegisterGetAll[Out any](pattern string, mux *http.ServeMux, f func(context.Context) ([]Out, error), opts ...HandlerOption) {
+	c := newHandlerConfig(opts)
+
 	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		format, ok := negotiateList[Out](r)
+		if !ok {
+			http.Error(w, "unsupported format", http.StatusNotAcceptable)
+			return
+		}
+
+		if c.countOnly(w, r) {
+			return
+		}
+
 		out, err := f(r.Context())
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
@@ -76,8 +121,26 @@ func RegisterGetAll[Out any](pattern string, mux *http.ServeMux, f func(context.
 			return
 		}
 
+		if !c.totalCount(w, r) {
+			return
+		}
+
+		if format.name != formatJSON {
+			enc := format.start(w)
+			for _, item := range out {
+				if err := enc.encode(item); err != nil {
+					log.Printf("failed to encode resources: %v", err)
+					return
+				}
+			}
+			if err := enc.end(); err != nil {
+				log.Printf("failed to encode resources: %v", err)
+			}
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusCreated)
+		w.WriteHeader(http.StatusOK)
 		err = json.NewEncoder(w).Encode(out)
 		if err != nil {
 			log.Printf("failed to encode created note: %v", err)
@@ -86,14 +149,20 @@ func RegisterGetAll[Out any](pattern string, mux *http.ServeMux, f func(context.
 	})
 }
 
-func RegisterDelete(pattern string, mux *http.ServeMux, f func(context.Context, int) error) {
+func RegisterDelete[K any](pattern string, mux *http.ServeMux, f func(context.Context, K) error, opts ...HandlerOption) {
+	c := newHandlerConfig(opts)
+
 	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
-		id, err := strconv.Atoi(r.PathValue("id"))
+		id, err := pathKey[K](r, c)
 		if err != nil {
 			http.Error(w, "invalid param", http.StatusBadRequest)
 			return
 		}
 
+		if !c.checkIfMatch(w, r, id) {
+			return
+		}
+
 		err = f(r.Context(), id)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
@@ -109,7 +178,9 @@ func RegisterDelete(pattern string, mux *http.ServeMux, f func(context.Context,
 	})
 }
 
-func RegisterUpdate[In Model](pattern string, mux *http.ServeMux, f func(ctx context.Context, in In, id int) error) {
+func RegisterUpdate[In Model, K any](pattern string, mux *http.ServeMux, f func(ctx context.Context, in In, id K) error, opts ...HandlerOption) {
+	c := newHandlerConfig(opts)
+
 	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
 		var in In
 
@@ -119,14 +190,22 @@ func RegisterUpdate[In Model](pattern string, mux *http.ServeMux, f func(ctx con
 			return
 		}
 
-		id, err := strconv.Atoi(r.PathValue("id"))
+		id, err := pathKey[K](r, c)
 		if err != nil {
 			http.Error(w, "invalid param", http.StatusBadRequest)
 			return
 		}
 
+		if !c.checkIfMatch(w, r, id) {
+			return
+		}
+
 		err = f(r.Context(), in, id)
 		if err != nil {
+			if errors.Is(err, ErrStaleObject) {
+				http.Error(w, "resource was modified", http.StatusConflict)
+				return
+			}
 			if errors.Is(err, sql.ErrNoRows) {
 				http.Error(w, "resource not found", http.StatusNotFound)
 				return
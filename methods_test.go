@@ -5,12 +5,14 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -96,6 +98,36 @@ func TestMethod_Create(t *testing.T) {
 	})
 }
 
+func TestMethod_CreateReturning(t *testing.T) {
+	t.Run("Test generic method: Create() - full object response", func(t *testing.T) {
+		mux := http.NewServeMux()
+		RegisterCreate("POST /item", mux, func(_ context.Context, in *Item) (*Item, error) {
+			in.ID = 7
+			return in, nil
+		})
+
+		var buf bytes.Buffer
+		err := json.NewEncoder(&buf).Encode(&Item{Name: "test 1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/item", &buf)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+
+		var got *Item
+		err = json.NewDecoder(res.Body).Decode(&got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, &Item{ID: 7, Name: "test 1"}, got)
+	})
+}
+
 func (mock *genericRepoMock[M]) Get(context.Context, int) (M, error) {
 	var zero M
 
@@ -181,6 +213,82 @@ func TestMethod_Get(t *testing.T) {
 	})
 }
 
+func TestMethod_GetConditional(t *testing.T) {
+	want := &Item{ID: 1, Name: "test 1"}
+	repo := &genericRepoMock[*Item]{t: t, model: want, table: "item"}
+	mux := http.NewServeMux()
+	RegisterGet(fmt.Sprintf("GET /%s/{id}", repo.GetTable()), mux, repo.Get)
+
+	req := httptest.NewRequest(http.MethodGet, "/item/1", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	res := rec.Result()
+	tag := res.Header.Get("ETag")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.NotEmpty(t, tag)
+
+	t.Run("Test generic method: Get() - If-None-Match matches", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/item/1", nil)
+		req.Header.Set("If-None-Match", tag)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		assert.Equal(t, http.StatusNotModified, res.StatusCode)
+		assert.Equal(t, 0, rec.Body.Len())
+	})
+
+	t.Run("Test generic method: Get() - If-None-Match differs", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/item/1", nil)
+		req.Header.Set("If-None-Match", `"other"`)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+}
+
+func TestMethod_GetKeyTypes(t *testing.T) {
+	t.Run("Test generic method: Get() - string key", func(t *testing.T) {
+		mux := http.NewServeMux()
+		RegisterGet("GET /item/{id}", mux, func(_ context.Context, id string) (*Item, error) {
+			return &Item{Name: id}, nil
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/item/d7e949b8-5c41-4972-b484-9c33b89af32c", nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		var got *Item
+		err := json.NewDecoder(rec.Result().Body).Decode(&got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "d7e949b8-5c41-4972-b484-9c33b89af32c", got.Name)
+	})
+
+	t.Run("Test generic method: Get() - custom key parser", func(t *testing.T) {
+		mux := http.NewServeMux()
+		parse := func(s string) (string, error) {
+			if len(s) != 4 {
+				return "", errors.New("invalid sku")
+			}
+			return s, nil
+		}
+		RegisterGet("GET /item/{id}", mux, func(_ context.Context, id string) (*Item, error) {
+			return &Item{Name: id}, nil
+		}, WithKeyParser(parse))
+
+		req := httptest.NewRequest(http.MethodGet, "/item/abcde", nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Result().StatusCode)
+	})
+}
+
 func (mock *genericRepoMock[M]) GetAll(context.Context) ([]M, error) {
 	var zero []M
 
@@ -227,6 +335,8 @@ func TestMethod_GetAll(t *testing.T) {
 		mux.ServeHTTP(rec, req)
 
 		res := rec.Result()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
 		var got []*Item
 		err = json.NewDecoder(res.Body).Decode(&got)
 		if err != nil {
@@ -254,6 +364,24 @@ func TestMethod_GetAll(t *testing.T) {
 
 		assert.Equal(t, "resource not found\n", string(errMsg))
 	})
+
+	t.Run("Test generic method: GetAll() - total count", func(t *testing.T) {
+		repo := &genericRepoMock[*Item]{t: t, models: []*Item{{ID: 1}, {ID: 2}}, table: "item"}
+		count := func(context.Context, sq.Sqlizer) (int64, error) { return 42, nil }
+		mux := http.NewServeMux()
+		RegisterGetAll(fmt.Sprintf("GET /%s", repo.GetTable()), mux, repo.GetAll, WithTotalCount(count))
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/item", nil))
+
+		assert.Equal(t, "42", rec.Header().Get("X-Total-Count"))
+
+		rec = httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/item?count_only=true", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"count":42}`, rec.Body.String())
+	})
 }
 
 func (mock *genericRepoMock[M]) Delete(context.Context, int) error {
@@ -321,6 +449,41 @@ func TestMethod_Delete(t *testing.T) {
 	})
 }
 
+func TestMethod_DeleteConditional(t *testing.T) {
+	current := &Item{ID: 1, Name: "test 1"}
+	tag, _, err := entityTag(current)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &genericRepoMock[*Item]{t: t, model: current, table: "item"}
+	mux := http.NewServeMux()
+	RegisterDelete(fmt.Sprintf("DELETE /%s/{id}", repo.GetTable()), mux, repo.Delete, RequireIfMatch(repo.Get))
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		want    int
+	}{
+		{name: "matching If-Match", ifMatch: tag, want: http.StatusOK},
+		{name: "stale If-Match", ifMatch: `"stale"`, want: http.StatusPreconditionFailed},
+		{name: "missing If-Match", want: http.StatusPreconditionRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run("Test generic method: Delete() - "+tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/item/1", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.want, rec.Result().StatusCode)
+		})
+	}
+}
+
 func (mock *genericRepoMock[M]) Update(context.Context, M, int) error {
 	if mock.err != nil {
 		return mock.err
@@ -394,6 +557,55 @@ func TestMethod_Update(t *testing.T) {
 		assert.Equal(t, "resource not found\n", string(errMsg))
 	})
 
+	t.Run("Test generic method: Update() - stale object", func(t *testing.T) {
+		want := &Item{
+			ID:   1,
+			Name: "test 1",
+		}
+
+		repo := &genericRepoMock[*Item]{table: "item", err: ErrStaleObject}
+		mux := http.NewServeMux()
+		RegisterUpdate(fmt.Sprintf("POST /%s/{id}", repo.GetTable()), mux, repo.Update)
+
+		var buf bytes.Buffer
+		err := json.NewEncoder(&buf).Encode(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/item/1", &buf)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+	})
+
+	t.Run("Test generic method: Update() - If-Match mismatch", func(t *testing.T) {
+		want := &Item{
+			ID:   1,
+			Name: "test 1",
+		}
+
+		repo := &genericRepoMock[*Item]{t: t, model: want, table: "item"}
+		mux := http.NewServeMux()
+		RegisterUpdate(fmt.Sprintf("POST /%s/{id}", repo.GetTable()), mux, repo.Update, WithIfMatch(repo.Get))
+
+		var buf bytes.Buffer
+		err := json.NewEncoder(&buf).Encode(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/item/1", &buf)
+		req.Header.Set("If-Match", `"stale"`)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		res := rec.Result()
+		assert.Equal(t, http.StatusPreconditionFailed, res.StatusCode)
+	})
+
 	t.Run("Test generic method: Update() - empty body", func(t *testing.T) {
 		repo := &genericRepoMock[*Item]{table: "item"}
 		mux := http.NewServeMux()
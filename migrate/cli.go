@@ -0,0 +1,72 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const usage = `usage: migrate <command>
+
+commands:
+  up        apply all pending migrations
+  down [n]  revert the last n applied migrations (default 1)
+  status    list migrations and whether they are applied`
+
+// Run executes the migration command given in args and reports progress to out. It lets an
+// application expose its embedded migrations as a subcommand:
+//
+//	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+//		if err := m.Run(ctx, os.Args[2:], os.Stdout); err != nil {
+//			log.Fatal(err)
+//		}
+//		return
+//	}
+func (m *Migrator) Run(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n%s", usage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, migration := range applied {
+			fmt.Fprintf(out, "applied %d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "no pending migrations")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+			steps = n
+		}
+
+		reverted, err := m.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Fprintf(out, "reverted %d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range status {
+			state := "pending"
+			if s.Applied {
+				state = "applied"
+			}
+			fmt.Fprintf(out, "%-8s %d_%s\n", state, s.Version, s.Name)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+}
@@ -0,0 +1,365 @@
+// Package migrate applies versioned SQL migrations shipped with the application, typically
+// through an embed.FS:
+//
+//	//go:embed migrations/*.sql
+//	var migrations embed.FS
+//
+//	sub, _ := fs.Sub(migrations, "migrations")
+//	m, err := migrate.New(db, sub, migrate.WithDialect(gocrud.Postgres))
+//	applied, err := m.Up(ctx)
+//
+// Migrations are files named <version>_<name>.up.sql with an optional matching
+// <version>_<name>.down.sql, where version is a positive integer. Each migration runs in its
+// own transaction together with the bookkeeping row in the versions table, so the script may
+// hold several statements if the driver supports it.
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+
+	sq "github.com/Masterminds/squirrel"
+
+	gocrud "github.com/tender-barbarian/go-crud"
+)
+
+// ErrLocked is returned when another run holds the migration lock.
+var ErrLocked = errors.New("migrations are locked by another run")
+
+// Migration is a single schema change.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status reports whether a migration has been applied.
+type Status struct {
+	Migration
+	Applied bool
+}
+
+type Option func(*Migrator)
+
+// WithTable sets the table recording applied versions, "schema_migrations" by default.
+func WithTable(name string) Option {
+	return func(m *Migrator) {
+		m.table = name
+	}
+}
+
+// WithDialect sets the SQL dialect, gocrud.Default if not given. It selects the placeholder
+// format, the column types of the versions table and the locking strategy.
+func WithDialect(d gocrud.Dialect) Option {
+	return func(m *Migrator) {
+		m.dialect = d
+	}
+}
+
+// Migrator applies migrations to a database.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	table      string
+	dialect    gocrud.Dialect
+}
+
+// New loads the migrations at the root of fsys.
+func New(db *sql.DB, fsys fs.FS, opts ...Option) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Migrator{db: db, migrations: migrations, table: "schema_migrations", dialect: gocrud.Default}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m, nil
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Load reads the migrations at the root of fsys, ordered by version. Other files are ignored.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: invalid version", entry.Name())
+		}
+
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d: conflicting names %s and %s", version, m.Name, match[2])
+		}
+
+		script := &m.Up
+		if match[3] == "down" {
+			script = &m.Down
+		}
+		if *script != "" {
+			return nil, fmt.Errorf("migration %d: duplicate %s script", version, match[3])
+		}
+		*script = string(body)
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d: missing up script", m.Version)
+		}
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
+
+// Migrations returns the loaded migrations, ordered by version.
+func (m *Migrator) Migrations() []Migration {
+	return slices.Clone(m.migrations)
+}
+
+// Up applies all pending migrations in order and returns the ones it applied. It stops at the
+// first failing migration, leaving the earlier ones applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.run(ctx, func(conn *sql.Conn, versions map[int64]bool) error {
+		for _, migration := range m.migrations {
+			if versions[migration.Version] {
+				continue
+			}
+
+			insert := m.builder().Insert(m.table).Columns("version", "name").Values(migration.Version, migration.Name)
+			if err := m.apply(ctx, conn, migration, migration.Up, insert); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts the last steps applied migrations, newest first, and returns the ones it reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+
+	err := m.run(ctx, func(conn *sql.Conn, versions map[int64]bool) error {
+		for _, migration := range slices.Backward(m.migrations) {
+			if len(reverted) == steps {
+				break
+			}
+			if !versions[migration.Version] {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %d: missing down script", migration.Version)
+			}
+
+			remove := m.builder().Delete(m.table).Where(sq.Eq{"version": migration.Version})
+			if err := m.apply(ctx, conn, migration, migration.Down, remove); err != nil {
+				return err
+			}
+			reverted = append(reverted, migration)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status lists the loaded migrations and whether each has been applied. It takes the migration
+// lock like Up and Down, so it fails with ErrLocked while another run is in progress.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var status []Status
+
+	err := m.run(ctx, func(_ *sql.Conn, versions map[int64]bool) error {
+		status = make([]Status, 0, len(m.migrations))
+		for _, migration := range m.migrations {
+			status = append(status, Status{Migration: migration, Applied: versions[migration.Version]})
+		}
+
+		return nil
+	})
+
+	return status, err
+}
+
+// run holds the migration lock on a single connection while fn executes.
+func (m *Migrator) run(ctx context.Context, fn func(*sql.Conn, map[int64]bool) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	unlock, err := m.lock(ctx, conn)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, unlock(context.WithoutCancel(ctx)))
+	}()
+
+	if err := m.createTable(ctx, conn); err != nil {
+		return err
+	}
+
+	versions, err := m.versions(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	return fn(conn, versions)
+}
+
+// apply runs script and the bookkeeping statement in one transaction.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration, script string, record sq.Sqlizer) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	query, args, err := record.ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) builder() sq.StatementBuilderType {
+	return sq.StatementBuilder.PlaceholderFormat(m.dialect.Placeholder)
+}
+
+func (m *Migrator) createTable(ctx context.Context, conn *sql.Conn) error {
+	types := m.dialect.Types
+	// MySQL requires the default to have the precision of its DATETIME(6) column.
+	now := "CURRENT_TIMESTAMP"
+	if m.dialect.Name == gocrud.MySQL.Name {
+		now = "CURRENT_TIMESTAMP(6)"
+	}
+
+	_, err := conn.ExecContext(ctx, fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s (version %s PRIMARY KEY, name %s NOT NULL, applied_at %s NOT NULL DEFAULT %s)",
+		m.table, types.BigInt, types.Text, types.Time, now))
+
+	return err
+}
+
+func (m *Migrator) versions(ctx context.Context, conn *sql.Conn) (map[int64]bool, error) {
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT version FROM %s", m.table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make(map[int64]bool)
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		versions[version] = true
+	}
+
+	return versions, rows.Err()
+}
+
+// lock takes the migration lock without waiting, returning ErrLocked if another run holds it:
+// an advisory lock on PostgreSQL and MySQL, otherwise a row in the <table>_lock table, which has
+// to be cleared by hand if a run crashes while holding it.
+func (m *Migrator) lock(ctx context.Context, conn *sql.Conn) (func(context.Context) error, error) {
+	switch m.dialect.Name {
+	case gocrud.Postgres.Name:
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(m.table))
+		key := int64(h.Sum64())
+
+		var acquired bool
+		if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+			return nil, err
+		}
+		if !acquired {
+			return nil, ErrLocked
+		}
+
+		return func(ctx context.Context) error {
+			_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", key)
+			return err
+		}, nil
+	case gocrud.MySQL.Name:
+		var acquired sql.NullInt64
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", m.table).Scan(&acquired); err != nil {
+			return nil, err
+		}
+		if acquired.Int64 != 1 {
+			return nil, ErrLocked
+		}
+
+		return func(ctx context.Context) error {
+			_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", m.table)
+			return err
+		}, nil
+	default:
+		lockTable := m.table + "_lock"
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (id INTEGER PRIMARY KEY)", lockTable)); err != nil {
+			return nil, err
+		}
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (id) VALUES (1)", lockTable)); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrLocked, err)
+		}
+
+		return func(ctx context.Context) error {
+			_, err := conn.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", lockTable))
+			return err
+		}, nil
+	}
+}
@@ -0,0 +1,245 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	gocrud "github.com/tender-barbarian/go-crud"
+)
+
+var migrations = fstest.MapFS{
+	"0002_add_email.up.sql":       {Data: []byte("ALTER TABLE users ADD COLUMN email TEXT")},
+	"0002_add_email.down.sql":     {Data: []byte("ALTER TABLE users DROP COLUMN email")},
+	"0001_create_users.up.sql":    {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY)")},
+	"0001_create_users.down.sql":  {Data: []byte("DROP TABLE users")},
+	"README.md":                   {Data: []byte("ignored")},
+	"0003_seed_users.up.sql":      {Data: []byte("INSERT INTO users (id) VALUES (1)")},
+	"fixtures/0004_nested.up.sql": {Data: []byte("ignored")},
+}
+
+func TestMigrate_Load(t *testing.T) {
+	t.Run("Test Load() orders migrations", func(t *testing.T) {
+		got, err := Load(migrations)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []Migration{
+			{Version: 1, Name: "create_users", Up: "CREATE TABLE users (id INTEGER PRIMARY KEY)", Down: "DROP TABLE users"},
+			{Version: 2, Name: "add_email", Up: "ALTER TABLE users ADD COLUMN email TEXT", Down: "ALTER TABLE users DROP COLUMN email"},
+			{Version: 3, Name: "seed_users", Up: "INSERT INTO users (id) VALUES (1)"},
+		}, got)
+	})
+
+	t.Run("Test Load() missing up script", func(t *testing.T) {
+		_, err := Load(fstest.MapFS{"0001_init.down.sql": {Data: []byte("DROP TABLE users")}})
+		assert.EqualError(t, err, "migration 1: missing up script")
+	})
+
+	t.Run("Test Load() conflicting names", func(t *testing.T) {
+		_, err := Load(fstest.MapFS{
+			"0001_init.up.sql": {Data: []byte("CREATE TABLE users (id INTEGER)")},
+			"1_other.down.sql": {Data: []byte("DROP TABLE users")},
+		})
+		assert.EqualError(t, err, "migration 1: conflicting names init and other")
+	})
+}
+
+func TestMigrate_Up(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	m, err := New(db, migrations, WithDialect(gocrud.SQLite))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createTable := regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP)")
+	lock := regexp.QuoteMeta("INSERT INTO schema_migrations_lock (id) VALUES (1)")
+	unlock := regexp.QuoteMeta("DELETE FROM schema_migrations_lock")
+	versions := regexp.QuoteMeta("SELECT version FROM schema_migrations")
+
+	t.Run("Test Up() applies pending migrations", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations_lock")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(lock).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(createTable).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(versions).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+
+		for _, migration := range []struct {
+			version int64
+			name    string
+			script  string
+		}{
+			{2, "add_email", "ALTER TABLE users ADD COLUMN email TEXT"},
+			{3, "seed_users", "INSERT INTO users (id) VALUES (1)"},
+		} {
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(migration.script)).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version,name) VALUES (?,?)")).
+				WithArgs(migration.version, migration.name).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}
+
+		mock.ExpectExec(unlock).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		applied, err := m.Up(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, applied, 2)
+		assert.Equal(t, int64(3), applied[1].Version)
+	})
+
+	t.Run("Test Up() failing migration is rolled back", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations_lock")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(lock).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(createTable).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(versions).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1).AddRow(2))
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO users (id) VALUES (1)")).WillReturnError(errors.New("constraint failed"))
+		mock.ExpectRollback()
+		mock.ExpectExec(unlock).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		applied, err := m.Up(context.Background())
+
+		assert.EqualError(t, err, "migration 3_seed_users: constraint failed")
+		assert.Empty(t, applied)
+	})
+
+	t.Run("Test Up() locked by another run", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations_lock")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(lock).WillReturnError(errors.New("UNIQUE constraint failed"))
+
+		_, err := m.Up(context.Background())
+
+		assert.ErrorIs(t, err, ErrLocked)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMigrate_Run(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	m, err := New(db, migrations, WithDialect(gocrud.Postgres), WithTable("versions"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Test Run() down", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_try_advisory_lock($1)")).
+			WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS versions")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version FROM versions")).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1).AddRow(2))
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE users DROP COLUMN email")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM versions WHERE version = $1")).WithArgs(int64(2)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		var out bytes.Buffer
+		if err := m.Run(context.Background(), []string{"down"}, &out); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "reverted 2_add_email\n", out.String())
+	})
+
+	t.Run("Test Run() locked by another run", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_try_advisory_lock($1)")).
+			WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+
+		err := m.Run(context.Background(), []string{"up"}, &bytes.Buffer{})
+
+		assert.ErrorIs(t, err, ErrLocked)
+	})
+
+	t.Run("Test Run() status", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_try_advisory_lock($1)")).
+			WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS versions")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version FROM versions")).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		var out bytes.Buffer
+		if err := m.Run(context.Background(), []string{"status"}, &out); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "applied  1_create_users\npending  2_add_email\npending  3_seed_users\n", out.String())
+	})
+
+	t.Run("Test Run() unknown command", func(t *testing.T) {
+		assert.Error(t, m.Run(context.Background(), []string{"sideways"}, &bytes.Buffer{}))
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMigrate_MySQL(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	m, err := New(db, migrations, WithDialect(gocrud.MySQL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Test Status() on MySQL", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT GET_LOCK(?, 0)")).WithArgs("schema_migrations").
+			WillReturnRows(sqlmock.NewRows([]string{"acquired"}).AddRow(1))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT PRIMARY KEY, name VARCHAR(255) NOT NULL, " +
+			"applied_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6))")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version FROM schema_migrations")).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1).AddRow(2))
+		mock.ExpectExec(regexp.QuoteMeta("SELECT RELEASE_LOCK(?)")).WithArgs("schema_migrations").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		status, err := m.Status(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, status, 3)
+		assert.True(t, status[1].Applied)
+		assert.False(t, status[2].Applied)
+	})
+
+	t.Run("Test Up() locked on MySQL", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT GET_LOCK(?, 0)")).WithArgs("schema_migrations").
+			WillReturnRows(sqlmock.NewRows([]string{"acquired"}).AddRow(0))
+
+		_, err := m.Up(context.Background())
+
+		assert.ErrorIs(t, err, ErrLocked)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,141 @@
+package gocrud
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Null is a nullable column value. Unlike sql.Null it encodes to JSON as the bare value or null.
+type Null[T any] struct {
+	sql.Null[T]
+}
+
+// NewNull returns a valid Null holding v.
+func NewNull[T any](v T) Null[T] {
+	return Null[T]{sql.Null[T]{V: v, Valid: true}}
+}
+
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(n.V)
+}
+
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		n.V, n.Valid = *new(T), false
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &n.V); err != nil {
+		return err
+	}
+	n.Valid = true
+
+	return nil
+}
+
+// nullZero wraps a non-pointer field tagged `db:",nullzero"` so that NULL scans as the zero value.
+type nullZero struct {
+	field reflect.Value
+}
+
+func (n nullZero) Scan(src any) error {
+	if src == nil {
+		n.field.SetZero()
+		return nil
+	}
+
+	return assign(n.field, src)
+}
+
+func (n nullZero) Value() (driver.Value, error) {
+	return driver.DefaultParameterConverter.ConvertValue(n.field.Interface())
+}
+
+func (n nullZero) fieldPtr() any {
+	return n.field.Addr().Interface()
+}
+
+// assign stores a non-nil driver value in dst, converting between the types drivers commonly return.
+func assign(dst reflect.Value, src any) error {
+	if s, ok := dst.Addr().Interface().(sql.Scanner); ok {
+		return s.Scan(src)
+	}
+
+	sv := reflect.ValueOf(src)
+	if sv.Type().AssignableTo(dst.Type()) {
+		dst.Set(sv)
+		return nil
+	}
+
+	text := ""
+	switch v := src.(type) {
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		if isNumber(sv.Kind()) && isNumber(dst.Kind()) {
+			dst.Set(sv.Convert(dst.Type()))
+			return nil
+		}
+		text = fmt.Sprint(v)
+	}
+
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(text, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(text, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(f)
+	default:
+		return fmt.Errorf("cannot assign %T to %s", src, dst.Type())
+	}
+
+	return nil
+}
+
+func isNumber(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// NullZero scans NULL into the field pointed to by ptr as its zero value. It is the
+// hand-written equivalent of the `db:",nullzero"` tag.
+func NullZero(ptr any) any {
+	return nullZero{field: reflect.ValueOf(ptr).Elem()}
+}
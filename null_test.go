@@ -0,0 +1,63 @@
+package gocrud
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNull_JSON(t *testing.T) {
+	t.Run("Test Null marshal", func(t *testing.T) {
+		got, err := json.Marshal(struct {
+			A Null[string] `json:"a"`
+			B Null[int]    `json:"b"`
+		}{A: NewNull("test")})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.JSONEq(t, `{"a": "test", "b": null}`, string(got))
+	})
+
+	t.Run("Test Null unmarshal", func(t *testing.T) {
+		var got struct {
+			A Null[string] `json:"a"`
+			B Null[int]    `json:"b"`
+		}
+		err := json.Unmarshal([]byte(`{"a": "test", "b": null}`), &got)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, NewNull("test"), got.A)
+		assert.False(t, got.B.Valid)
+	})
+}
+
+func TestNull_Assign(t *testing.T) {
+	tests := []struct {
+		name string
+		src  any
+		dst  any
+		want any
+	}{
+		{name: "bytes to string", src: []byte("test"), dst: new(string), want: "test"},
+		{name: "int64 to int", src: int64(42), dst: new(int), want: 42},
+		{name: "bytes to int", src: []byte("42"), dst: new(int32), want: int32(42)},
+		{name: "float64 to float32", src: 1.5, dst: new(float32), want: float32(1.5)},
+		{name: "int64 to bool", src: int64(1), dst: new(bool), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run("Test assign "+tt.name, func(t *testing.T) {
+			dst := reflect.ValueOf(tt.dst).Elem()
+			if err := assign(dst, tt.src); err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, tt.want, dst.Interface())
+		})
+	}
+}
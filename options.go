@@ -0,0 +1,83 @@
+package gocrud
+
+const softDeleteColumn = "deleted_at"
+
+// IDStrategy controls where the value of a single-column primary key comes from on Create.
+type IDStrategy int
+
+const (
+	// DatabaseID leaves the key out of the INSERT and reads the generated value back.
+	DatabaseID IDStrategy = iota
+	// GeneratedID fills the key from the callback given to WithIDGenerator before inserting.
+	GeneratedID
+	// SuppliedID inserts the key already set on the model by the caller.
+	SuppliedID
+)
+
+type Option func(*config)
+
+type config struct {
+	dialect       Dialect
+	keyColumns    []string
+	idStrategy    IDStrategy
+	newID         func() any
+	softDelete    bool
+	versionColumn string
+	converters    converters
+}
+
+// KeyColumner is implemented by models that name their primary key columns, such as the models
+// generated by gocrud-gen.
+type KeyColumner interface {
+	KeyColumns() []string
+}
+
+// WithPrimaryKey sets the primary key column(s). Without it, the key columns come from the
+// model's KeyColumns method if it implements KeyColumner, and are "id" otherwise. With several
+// columns the key type must implement Model, e.g. a struct embedding Reflection whose fields are
+// the key columns.
+func WithPrimaryKey(columns ...string) Option {
+	return func(c *config) {
+		c.keyColumns = columns
+	}
+}
+
+// WithSoftDelete makes Delete set the deleted_at column instead of removing the row.
+// Soft-deleted rows are excluded from Get and GetAll unless WithDeleted is used.
+func WithSoftDelete() Option {
+	return func(c *config) {
+		c.softDelete = true
+	}
+}
+
+// WithVersionColumn enables optimistic locking on the given integer column.
+// Update only matches the row if the column still holds the model's version, and increments it.
+func WithVersionColumn(column string) Option {
+	return func(c *config) {
+		c.versionColumn = column
+	}
+}
+
+// WithIDStrategy sets how Create obtains the primary key. Composite keys are always supplied by the caller.
+func WithIDStrategy(strategy IDStrategy) Option {
+	return func(c *config) {
+		c.idStrategy = strategy
+	}
+}
+
+// WithIDGenerator makes Create assign keys generated by the application, e.g. UUIDv7 values.
+func WithIDGenerator[K any](generate func() K) Option {
+	return func(c *config) {
+		c.idStrategy = GeneratedID
+		c.newID = func() any {
+			return generate()
+		}
+	}
+}
+
+// WithDialect sets the SQL dialect, Default if not given.
+func WithDialect(d Dialect) Option {
+	return func(c *config) {
+		c.dialect = d
+	}
+}
@@ -0,0 +1,110 @@
+package gocrud
+
+import (
+	"context"
+	"database/sql"
+	"maps"
+	"slices"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// SelectQuery reads rows from a repository's table, possibly joined with other tables, into
+// a result type D that need not be the repository's model, e.g. a denormalized view.
+type SelectQuery[D Model] struct {
+	db     *sql.DB
+	config config
+	newRow func() D
+	b      sq.SelectBuilder
+}
+
+// Select starts a query over the repository's table whose rows are scanned into values created
+// by newRow through their StructToMap. Without columns, the columns mapped by D are selected;
+// columns shared by joined tables have to be given explicitly, e.g. "orders.id" or
+// "customers.name AS customer_name". Soft-deleted rows and rows outside the repository's scope
+// are excluded.
+func Select[D Model, M Model, K comparable](r *Repository[M, K], newRow func() D, columns ...string) *SelectQuery[D] {
+	if len(columns) == 0 {
+		row := newRow()
+		columns = slices.Sorted(maps.Keys(row.StructToMap(row)))
+	}
+
+	b := r.builder().Select(columns...).From(r.table)
+	for _, column := range slices.Sorted(maps.Keys(r.scopes)) {
+		b = b.Where(sq.Eq{r.table + "." + column: r.scopes[column]})
+	}
+	if r.config.softDelete && !r.withDeleted {
+		b = b.Where(sq.Eq{r.table + "." + softDeleteColumn: nil})
+	}
+
+	return &SelectQuery[D]{db: r.db, config: r.config, newRow: newRow, b: b}
+}
+
+func (q *SelectQuery[D]) with(b sq.SelectBuilder) *SelectQuery[D] {
+	c := *q
+	c.b = b
+
+	return &c
+}
+
+// Join adds an INNER JOIN of table on the given condition, e.g.
+// Join("customers", "customers.id = orders.customer_id").
+func (q *SelectQuery[D]) Join(table, on string, args ...any) *SelectQuery[D] {
+	return q.with(q.b.Join(table+" ON "+on, args...))
+}
+
+// LeftJoin adds a LEFT JOIN of table on the given condition.
+func (q *SelectQuery[D]) LeftJoin(table, on string, args ...any) *SelectQuery[D] {
+	return q.with(q.b.LeftJoin(table+" ON "+on, args...))
+}
+
+// Where adds a condition, either a SQL fragment with ? placeholders or a squirrel expression
+// such as sq.Eq.
+func (q *SelectQuery[D]) Where(pred any, args ...any) *SelectQuery[D] {
+	return q.with(q.b.Where(pred, args...))
+}
+
+func (q *SelectQuery[D]) GroupBy(columns ...string) *SelectQuery[D] {
+	return q.with(q.b.GroupBy(columns...))
+}
+
+func (q *SelectQuery[D]) Having(pred any, args ...any) *SelectQuery[D] {
+	return q.with(q.b.Having(pred, args...))
+}
+
+func (q *SelectQuery[D]) OrderBy(columns ...string) *SelectQuery[D] {
+	return q.with(q.b.OrderBy(columns...))
+}
+
+func (q *SelectQuery[D]) Limit(n uint64) *SelectQuery[D] {
+	return q.with(q.b.Limit(n))
+}
+
+func (q *SelectQuery[D]) Offset(n uint64) *SelectQuery[D] {
+	return q.with(q.b.Offset(n))
+}
+
+// ToSql returns the SQL of the query and its arguments.
+func (q *SelectQuery[D]) ToSql() (string, []any, error) {
+	return q.b.ToSql()
+}
+
+// All returns every row of the query.
+func (q *SelectQuery[D]) All(ctx context.Context) ([]D, error) {
+	return queryAll(ctx, q.db, q.config, q.b, q.newRow)
+}
+
+// One returns the first row of the query, or sql.ErrNoRows.
+func (q *SelectQuery[D]) One(ctx context.Context) (D, error) {
+	var zero D
+
+	rows, err := queryAll(ctx, q.db, q.config, q.b.Limit(1), q.newRow)
+	if err != nil {
+		return zero, err
+	}
+	if len(rows) == 0 {
+		return zero, sql.ErrNoRows
+	}
+
+	return rows[0], nil
+}
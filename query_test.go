@@ -0,0 +1,71 @@
+package gocrud
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type OrderView struct {
+	ID           int         `json:"id"`
+	CustomerName string      `json:"customer_name" db:"customer_name"`
+	Items        Null[int64] `json:"items"`
+	Reflection
+}
+
+func TestQuery_Select(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	orders := NewGenericRepository(db, "orders", func() *Order { return &Order{} }, WithSoftDelete())
+	newView := func() *OrderView { return &OrderView{} }
+
+	t.Run("Test Select() with joins", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT orders.id, customers.name AS customer_name, COUNT(order_items.id) AS items " +
+			"FROM orders JOIN customers ON customers.id = orders.customer_id " +
+			"LEFT JOIN order_items ON order_items.order_id = orders.id " +
+			"WHERE orders.deleted_at IS NULL AND customers.name LIKE ? " +
+			"GROUP BY orders.id, customers.name ORDER BY orders.id LIMIT 10")).
+			WithArgs("A%").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "customer_name", "items"}).
+				AddRow(1, "Ada", 2).AddRow(2, "Alan", nil))
+
+		got, err := Select(orders, newView, "orders.id", "customers.name AS customer_name", "COUNT(order_items.id) AS items").
+			Join("customers", "customers.id = orders.customer_id").
+			LeftJoin("order_items", "order_items.order_id = orders.id").
+			Where("customers.name LIKE ?", "A%").
+			GroupBy("orders.id", "customers.name").
+			OrderBy("orders.id").
+			Limit(10).
+			All(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []*OrderView{
+			{ID: 1, CustomerName: "Ada", Items: NewNull[int64](2)},
+			{ID: 2, CustomerName: "Alan"},
+		}, got)
+	})
+
+	t.Run("Test Select() default columns", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT customer_name, id, items FROM orders WHERE orders.deleted_at IS NULL AND id = ? LIMIT 1")).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"customer_name", "id", "items"}))
+
+		_, err := Select(orders, newView).Where(map[string]any{"id": 3}).One(context.Background())
+
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,197 @@
+package gocrud
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Query runs a hand-written SELECT and scans each row into a model through StructToMap, ignoring
+// columns the model does not map. Arguments are either positional, using the dialect's own
+// placeholders, or named: sql.Named values or a single map[string]any, referenced as :name and
+// rewritten to the dialect's placeholders.
+//
+//	repo.Query(ctx, "SELECT * FROM devices WHERE type = :type AND ip LIKE :net", sql.Named("type", "esp32"), sql.Named("net", "10.%"))
+func (r *Repository[M, K]) Query(ctx context.Context, query string, args ...any) ([]M, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	raw, err := r.raw(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return queryAll(ctx, r.db, r.config, raw, r.getConcreteType)
+}
+
+// QueryOne is like Query but returns only the first row, or sql.ErrNoRows.
+func (r *Repository[M, K]) QueryOne(ctx context.Context, query string, args ...any) (M, error) {
+	var zero M
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	raw, err := r.raw(query, args)
+	if err != nil {
+		return zero, err
+	}
+
+	model := r.getConcreteType()
+	if err := r.queryOne(ctx, raw, model); err != nil {
+		return zero, err
+	}
+
+	return model, nil
+}
+
+// rawSQL is a hand-written statement, usable where a squirrel builder is expected.
+type rawSQL struct {
+	query string
+	args  []any
+}
+
+func (s rawSQL) ToSql() (string, []any, error) {
+	return s.query, s.args, nil
+}
+
+func (r *Repository[M, K]) raw(query string, args []any) (rawSQL, error) {
+	named, err := namedArgs(args)
+	if err != nil || named == nil {
+		return rawSQL{query: query, args: args}, err
+	}
+
+	query, args, err = bindNamed(query, named, r.config.dialect)
+	if err != nil {
+		return rawSQL{}, err
+	}
+
+	return rawSQL{query: query, args: args}, nil
+}
+
+// namedArgs collects named arguments, or returns nil if args are positional.
+func namedArgs(args []any) (map[string]any, error) {
+	if len(args) == 1 {
+		if m, ok := args[0].(map[string]any); ok {
+			return m, nil
+		}
+	}
+
+	count := 0
+	named := make(map[string]any, len(args))
+	for _, arg := range args {
+		if n, ok := arg.(sql.NamedArg); ok {
+			named[n.Name] = n.Value
+			count++
+		}
+	}
+
+	switch count {
+	case 0:
+		return nil, nil
+	case len(args):
+		return named, nil
+	default:
+		return nil, errors.New("cannot mix named and positional arguments")
+	}
+}
+
+// bindNamed replaces the :name parameters of query with placeholders of the dialect, returning
+// the matching arguments. String literals, quoted identifiers, comments and PostgreSQL :: casts
+// are left untouched.
+func bindNamed(query string, named map[string]any, d Dialect) (string, []any, error) {
+	var b strings.Builder
+	var parts []string
+	var args []any
+
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := i + 1
+			for end < len(runes) && runes[end] != c {
+				if d.BackslashEscapes && c != '`' && runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			b.WriteString(string(runes[i:min(end+1, len(runes))]))
+			i = end
+		case c == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			end := i + 2
+			for end < len(runes) && runes[end] != '\n' {
+				end++
+			}
+			b.WriteString(string(runes[i:end]))
+			i = end - 1
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end := i + 2
+			for end+1 < len(runes) && (runes[end] != '*' || runes[end+1] != '/') {
+				end++
+			}
+			end = min(end+2, len(runes))
+			b.WriteString(string(runes[i:end]))
+			i = end - 1
+		case c == ':' && i+1 < len(runes) && runes[i+1] == ':':
+			b.WriteString("::")
+			i++
+		case c == ':' && i+1 < len(runes) && isParamRune(runes[i+1]):
+			end := i + 1
+			for end < len(runes) && isParamRune(runes[end]) {
+				end++
+			}
+			name := string(runes[i+1 : end])
+			value, ok := named[name]
+			if !ok {
+				return "", nil, fmt.Errorf("missing value for parameter :%s", name)
+			}
+			parts = append(parts, b.String())
+			b.Reset()
+			args = append(args, value)
+			i = end - 1
+		default:
+			b.WriteRune(c)
+		}
+	}
+
+	parts = append(parts, b.String())
+
+	placeholders, err := placeholders(d.Placeholder, len(args))
+	if err != nil {
+		return "", nil, err
+	}
+
+	var out strings.Builder
+	for i, part := range parts {
+		out.WriteString(part)
+		if i < len(placeholders) {
+			out.WriteString(placeholders[i])
+		}
+	}
+
+	return out.String(), args, nil
+}
+
+// placeholders returns the first n bind variables of format, e.g. $1 to $n for sq.Dollar.
+func placeholders(format sq.PlaceholderFormat, n int) ([]string, error) {
+	if n == 0 {
+		return nil, nil
+	}
+
+	list, err := format.ReplacePlaceholders(strings.Repeat("?,", n))
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(list, ",")[:n], nil
+}
+
+func isParamRune(c rune) bool {
+	return c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
@@ -0,0 +1,138 @@
+package gocrud
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRaw_Query(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewGenericRepository(db, "devices", func() *ModelWithReflection { return &ModelWithReflection{} }, WithDialect(Postgres))
+
+	t.Run("Test Query() named parameters", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT d.*, 'x:y' AS note FROM devices d WHERE d.type = $1 AND d.ip::text LIKE $2 OR d.chip = $3")).
+			WithArgs("esp32", "10.%", "esp32").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "type", "note"}).
+				AddRow(1, "a", "esp32", "x:y").AddRow(2, "b", "esp32", "x:y"))
+
+		got, err := repo.Query(context.Background(),
+			"SELECT d.*, 'x:y' AS note FROM devices d WHERE d.type = :type AND d.ip::text LIKE :net OR d.chip = :type",
+			sql.Named("type", "esp32"), sql.Named("net", "10.%"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []*ModelWithReflection{{ID: 1, Name: "a", Type: "esp32"}, {ID: 2, Name: "b", Type: "esp32"}}, got)
+	})
+
+	t.Run("Test Query() question mark literal", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM devices WHERE name = $1 AND note <> '?' AND id::text = $2")).
+			WithArgs("a", "1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+
+		got, err := repo.Query(context.Background(),
+			"SELECT * FROM devices WHERE name = :n AND note <> '?' AND id::text = :id",
+			sql.Named("n", "a"), sql.Named("id", "1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []*ModelWithReflection{{ID: 1, Name: "a"}}, got)
+	})
+
+	t.Run("Test Query() comments", func(t *testing.T) {
+		query := "SELECT * FROM devices -- don't bind :skipped\nWHERE name = :n /* it's :skipped too */ AND type = :t"
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM devices -- don't bind :skipped\nWHERE name = $1 /* it's :skipped too */ AND type = $2")).
+			WithArgs("a", "esp32").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+
+		_, err := repo.Query(context.Background(), query, sql.Named("n", "a"), sql.Named("t", "esp32"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Test Query() scan failure closes rows", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM devices")).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("x", "a")).
+			RowsWillBeClosed()
+
+		_, err := repo.Query(context.Background(), "SELECT * FROM devices")
+		assert.Error(t, err)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM devices")).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("x", "a")).
+			RowsWillBeClosed()
+
+		_, err = repo.QueryOne(context.Background(), "SELECT * FROM devices")
+		assert.Error(t, err)
+	})
+
+	t.Run("Test QueryOne() map parameters", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM devices WHERE name = $1")).WithArgs("missing").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := repo.QueryOne(context.Background(), "SELECT * FROM devices WHERE name = :name", map[string]any{"name": "missing"})
+
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("Test QueryOne() positional parameters", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM devices WHERE id = $1")).WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "c"))
+
+		got, err := repo.QueryOne(context.Background(), "SELECT * FROM devices WHERE id = $1", 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, &ModelWithReflection{ID: 3, Name: "c"}, got)
+	})
+
+	t.Run("Test Query() invalid parameters", func(t *testing.T) {
+		_, err := repo.Query(context.Background(), "SELECT * FROM devices WHERE id = :id", sql.Named("name", "a"))
+		assert.EqualError(t, err, "missing value for parameter :id")
+
+		_, err = repo.Query(context.Background(), "SELECT * FROM devices WHERE id = :id", sql.Named("id", 1), 2)
+		assert.Error(t, err)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRaw_MySQL(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewGenericRepository(db, "devices", func() *ModelWithReflection { return &ModelWithReflection{} }, WithDialect(MySQL))
+
+	t.Run("Test Query() backslash-escaped quotes", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM devices WHERE note <> 'it\'s :skipped' AND name = ?`)).
+			WithArgs("a").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+
+		_, err := repo.Query(context.Background(), `SELECT * FROM devices WHERE note <> 'it\'s :skipped' AND name = :n`, sql.Named("n", "a"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -7,18 +7,75 @@ import (
 
 type Reflection struct{}
 
+// StructToMap maps column names to field pointers. The column name is the lowercased field
+// name unless the field has a `db:"name"` tag; fields tagged `db:"-"` are skipped.
+// The `nullzero` tag option scans NULL into a non-pointer field as its zero value, and the
+// `json` option stores a struct, map or slice field as a JSON document. Fields whose type has
+// a converter registered with RegisterConverter are encoded and decoded with it.
 func (r *Reflection) StructToMap(d interface{}) map[string]any {
 	m := make(map[string]interface{})
 
 	val := reflect.ValueOf(d).Elem()
 	for i := 0; i < val.NumField(); i++ {
-		name := strings.ToLower(val.Type().Field(i).Name)
+		field := val.Type().Field(i)
+		name := strings.ToLower(field.Name)
 		if name == "reflection" {
 			continue
 		}
+		tag, options := parseTag(field.Tag.Get("db"))
+		if tag == "-" {
+			continue
+		}
+		if tag != "" {
+			name = tag
+		}
+		if options.has("json") {
+			m[name] = jsonColumn{field: val.Field(i)}
+			continue
+		}
+		if options.has("nullzero") {
+			m[name] = nullZero{field: val.Field(i)}
+			continue
+		}
+		if c, ok := globalConverter(field.Type); ok {
+			m[name] = convertedColumn{field: val.Field(i), converter: c}
+			continue
+		}
 		ptr := val.Field(i).Addr().Interface()
 		m[name] = ptr
 	}
 
 	return m
 }
+
+// tagOptions holds the options of a `db` tag, mapping each option to its value, e.g.
+// `db:"name,unique,default=now()"` yields unique:"" and default:"now()".
+type tagOptions map[string]string
+
+func (o tagOptions) has(option string) bool {
+	_, ok := o[option]
+	return ok
+}
+
+func parseTag(tag string) (string, tagOptions) {
+	name, rest, _ := strings.Cut(tag, ",")
+
+	options := make(tagOptions)
+	for _, option := range strings.Split(rest, ",") {
+		if option != "" {
+			key, value, _ := strings.Cut(option, "=")
+			options[key] = value
+		}
+	}
+
+	return name, options
+}
+
+// fieldValue returns the struct field behind a StructToMap entry, unwrapping column adapters.
+func fieldValue(p any) reflect.Value {
+	if a, ok := p.(interface{ fieldPtr() any }); ok {
+		p = a.fieldPtr()
+	}
+
+	return reflect.Indirect(reflect.ValueOf(p))
+}
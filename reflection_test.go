@@ -60,3 +60,25 @@ func TestReflection_StructToMap(t *testing.T) {
 		}
 	})
 }
+
+func TestReflection_StructToMapTags(t *testing.T) {
+	t.Run("Test Struct To Map - db tags", func(t *testing.T) {
+		i := &struct {
+			TenantID int    `db:"tenant_id"`
+			SKU      string `db:"sku,omitempty"`
+			Ignored  string `db:"-"`
+			Name     string
+		}{}
+
+		want := map[string]any{
+			"tenant_id": &i.TenantID,
+			"sku":       &i.SKU,
+			"name":      &i.Name,
+		}
+
+		r := &Reflection{}
+		if got := r.StructToMap(i); !reflect.DeepEqual(got, want) {
+			t.Errorf("Reflection.StructToMap() = %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,232 @@
+package gocrud
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+var ErrUnknownRelation = errors.New("relation is not declared")
+
+// relation loads the models related to a repository's models through a pair of columns.
+type relation struct {
+	// column is the column of the declaring model whose values are looked up in the related table.
+	column string
+	// many reports whether the field holds a slice of related models.
+	many bool
+	// fetch returns the related models whose matching column holds one of values, along with
+	// each model's value of that column.
+	fetch func(ctx context.Context, values []any) ([]Model, []any, error)
+}
+
+// HasMany declares that rows of child reference the rows of parent through the fk column.
+// Preloading field assigns the children to that field of the parent model, a slice of C or of
+// the type C points to. The field must be tagged `db:"-"`. It panics if parent has a composite
+// primary key.
+func HasMany[M Model, K comparable, C Model, CK comparable](parent *Repository[M, K], field string, child *Repository[C, CK], fk string) {
+	parent.relate(field, relation{
+		column: parent.keyColumn("HasMany"),
+		many:   true,
+		fetch:  child.fetchBy(fk),
+	})
+}
+
+// BelongsTo declares that the fk column of child references the primary key of parent.
+// Preloading field assigns the parent to that field of the child model, of type P or the type
+// P points to. The field must be tagged `db:"-"`. It panics if parent has a composite primary key.
+func BelongsTo[M Model, K comparable, P Model, PK comparable](child *Repository[M, K], field string, parent *Repository[P, PK], fk string) {
+	child.relate(field, relation{
+		column: fk,
+		fetch:  parent.fetchBy(parent.keyColumn("BelongsTo")),
+	})
+}
+
+// keyColumn returns the primary key column for the relation declared by decl, panicking if the
+// key spans several columns, which relations cannot reference.
+func (r *Repository[M, K]) keyColumn(decl string) string {
+	if len(r.config.keyColumns) != 1 {
+		panic(fmt.Sprintf("gocrud: %s on %s: relations need a single-column primary key, not %v", decl, r.table, r.config.keyColumns))
+	}
+
+	return r.config.keyColumns[0]
+}
+
+func (r *Repository[M, K]) relate(field string, rel relation) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.relations[field] = rel
+}
+
+// fetchBy returns a relation fetcher selecting the repository's rows by the given column.
+func (r *Repository[M, K]) fetchBy(column string) func(context.Context, []any) ([]Model, []any, error) {
+	return func(ctx context.Context, values []any) ([]Model, []any, error) {
+		models, err := r.list(ctx, sq.Eq{column: values})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		related := make([]Model, 0, len(models))
+		keys := make([]any, 0, len(models))
+		for _, m := range models {
+			key, _ := columnValue(r.fields(m)[column])
+			related = append(related, m)
+			keys = append(keys, key)
+		}
+
+		return related, keys, nil
+	}
+}
+
+// Preload returns a view of the repository whose Get, GetByKey and GetAll also load the
+// relations declared on the given fields with HasMany, BelongsTo or ManyToMany, in one or
+// two queries per relation.
+func (r *Repository[M, K]) Preload(fields ...string) *Repository[M, K] {
+	v := r.view()
+	v.preload = append(slices.Clip(r.preload), fields...)
+
+	return v
+}
+
+func (r *Repository[M, K]) preloadInto(ctx context.Context, models []M) error {
+	for _, field := range r.preload {
+		r.mutex.Lock()
+		rel, ok := r.relations[field]
+		r.mutex.Unlock()
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownRelation, field)
+		}
+
+		if err := r.load(ctx, field, rel, models); err != nil {
+			return fmt.Errorf("preload %s: %w", field, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository[M, K]) load(ctx context.Context, field string, rel relation, models []M) error {
+	values := make([]any, 0, len(models))
+	seen := make(map[string]bool, len(models))
+	for _, m := range models {
+		v, ok := columnValue(r.fields(m)[rel.column])
+		if ok && !seen[matchKey(v)] {
+			seen[matchKey(v)] = true
+			values = append(values, v)
+		}
+	}
+
+	grouped := make(map[string][]Model)
+	if len(values) > 0 {
+		related, keys, err := rel.fetch(ctx, values)
+		if err != nil {
+			return err
+		}
+		for i, m := range related {
+			grouped[matchKey(keys[i])] = append(grouped[matchKey(keys[i])], m)
+		}
+	}
+
+	for _, m := range models {
+		dst := reflect.Indirect(reflect.ValueOf(m)).FieldByName(field)
+		if !dst.IsValid() || !dst.CanSet() {
+			return fmt.Errorf("%T has no settable field %s", m, field)
+		}
+
+		var matches []Model
+		if v, ok := columnValue(r.fields(m)[rel.column]); ok {
+			matches = grouped[matchKey(v)]
+		}
+
+		if err := assignRelated(dst, matches, rel.many); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// assignRelated stores the related models in dst: all of them if many, else the first one.
+func assignRelated(dst reflect.Value, related []Model, many bool) error {
+	if !many {
+		dst.SetZero()
+		if len(related) == 0 {
+			return nil
+		}
+		v, err := convertRelated(related[0], dst.Type())
+		if err != nil {
+			return err
+		}
+		dst.Set(v)
+		return nil
+	}
+
+	if dst.Kind() != reflect.Slice {
+		return fmt.Errorf("field of type %s cannot hold many related models", dst.Type())
+	}
+
+	s := reflect.MakeSlice(dst.Type(), 0, len(related))
+	for _, m := range related {
+		v, err := convertRelated(m, dst.Type().Elem())
+		if err != nil {
+			return err
+		}
+		s = reflect.Append(s, v)
+	}
+	dst.Set(s)
+
+	return nil
+}
+
+// convertRelated returns m as a value of type t, dereferencing it if t is the type it points to.
+func convertRelated(m Model, t reflect.Type) (reflect.Value, error) {
+	v := reflect.ValueOf(m)
+	switch {
+	case v.Type().AssignableTo(t):
+		return v, nil
+	case v.Kind() == reflect.Pointer && v.Type().Elem().AssignableTo(t):
+		return v.Elem(), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot assign %s to a field of type %s", v.Type(), t)
+	}
+}
+
+// columnValue returns the value of a StructToMap entry for use in a lookup, or false if it is NULL.
+func columnValue(p any) (any, bool) {
+	if p == nil {
+		return nil, false
+	}
+
+	v := fieldValue(p)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+
+	value := v.Interface()
+	if valuer, ok := value.(driver.Valuer); ok {
+		dv, err := valuer.Value()
+		if err != nil || dv == nil {
+			return nil, false
+		}
+		return dv, true
+	}
+
+	return value, true
+}
+
+// matchKey normalises a column value so that e.g. an int key matches an int64 foreign key.
+func matchKey(v any) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+
+	return fmt.Sprint(v)
+}
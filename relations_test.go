@@ -0,0 +1,172 @@
+package gocrud
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type Customer struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Reflection
+}
+
+type Order struct {
+	ID         int          `json:"id"`
+	CustomerID int64        `json:"customer_id" db:"customer_id"`
+	Customer   *Customer    `json:"customer,omitempty" db:"-"`
+	Items      []*OrderItem `json:"items,omitempty" db:"-"`
+	Reflection
+}
+
+type OrderItem struct {
+	ID      int    `json:"id"`
+	OrderID int    `json:"order_id" db:"order_id"`
+	SKU     string `json:"sku"`
+	Reflection
+}
+
+func TestRelations_Preload(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	customers := NewGenericRepository(db, "customers", func() *Customer { return &Customer{} })
+	orders := NewGenericRepository(db, "orders", func() *Order { return &Order{} })
+	items := NewGenericRepository(db, "order_items", func() *OrderItem { return &OrderItem{} })
+
+	BelongsTo(orders, "Customer", customers, "customer_id")
+	HasMany(orders, "Items", items, "order_id")
+
+	t.Run("Test Preload() on GetAll", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM orders ORDER BY id")).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "customer_id"}).AddRow(1, 7).AddRow(2, 7).AddRow(3, 8))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM order_items WHERE order_id IN (?,?,?) ORDER BY id")).
+			WithArgs(1, 2, 3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "sku"}).
+				AddRow(10, 1, "a").AddRow(11, 1, "b").AddRow(12, 3, "c"))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM customers WHERE id IN (?,?) ORDER BY id")).
+			WithArgs(int64(7), int64(8)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(7, "Ada").AddRow(8, "Linus"))
+
+		got, err := orders.Preload("Items", "Customer").GetAll(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ada, linus := &Customer{ID: 7, Name: "Ada"}, &Customer{ID: 8, Name: "Linus"}
+		assert.Equal(t, []*Order{
+			{ID: 1, CustomerID: 7, Customer: ada, Items: []*OrderItem{{ID: 10, OrderID: 1, SKU: "a"}, {ID: 11, OrderID: 1, SKU: "b"}}},
+			{ID: 2, CustomerID: 7, Customer: ada, Items: []*OrderItem{}},
+			{ID: 3, CustomerID: 8, Customer: linus, Items: []*OrderItem{{ID: 12, OrderID: 3, SKU: "c"}}},
+		}, got)
+	})
+
+	t.Run("Test Preload() on Get", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM orders WHERE id = ? LIMIT 1")).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "customer_id"}).AddRow(1, 7))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM order_items WHERE order_id IN (?) ORDER BY id")).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "sku"}).AddRow(10, 1, "a"))
+
+		got, err := orders.Preload("Items").Get(context.Background(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, &Order{ID: 1, CustomerID: 7, Items: []*OrderItem{{ID: 10, OrderID: 1, SKU: "a"}}}, got)
+	})
+
+	t.Run("Test Preload() unknown relation", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM orders WHERE id = ? LIMIT 1")).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "customer_id"}).AddRow(1, 7))
+
+		_, err := orders.Preload("Invoices").Get(context.Background(), 1)
+
+		assert.ErrorIs(t, err, ErrUnknownRelation)
+	})
+
+	t.Run("Test Preload() relation declared on the base repository", func(t *testing.T) {
+		view := items.Preload("Order")
+		assert.Same(t, items.mutex, view.mutex)
+
+		BelongsTo(items, "Order", orders, "order_id")
+		rel, ok := view.relations["Order"]
+
+		assert.True(t, ok)
+		assert.Equal(t, "order_id", rel.column)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRelations_CompositeKey(t *testing.T) {
+	products := NewRepository[*Product, SKUKey](nil, "products", func() *Product { return &Product{} }, WithPrimaryKey("tenant_id", "sku"))
+	items := NewGenericRepository(nil, "order_items", func() *OrderItem { return &OrderItem{} })
+
+	t.Run("Test HasMany() on a composite key", func(t *testing.T) {
+		assert.PanicsWithValue(t, "gocrud: HasMany on products: relations need a single-column primary key, not [tenant_id sku]", func() {
+			HasMany(products, "Items", items, "product_id")
+		})
+	})
+
+	t.Run("Test BelongsTo() on a composite key", func(t *testing.T) {
+		assert.Panics(t, func() {
+			BelongsTo(items, "Product", products, "product_id")
+		})
+	})
+}
+
+func TestGenericRepository_WithScope(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	items := NewGenericRepository(db, "order_items", func() *OrderItem { return &OrderItem{} }).WithScope("order_id", int64(4))
+
+	t.Run("Test WithScope() GetAll", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM order_items WHERE order_id = ? ORDER BY id")).WithArgs(int64(4)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "sku"}).AddRow(1, 4, "a"))
+
+		got, err := items.GetAll(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []*OrderItem{{ID: 1, OrderID: 4, SKU: "a"}}, got)
+	})
+
+	t.Run("Test WithScope() Create sets the column", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO order_items")).WillReturnResult(sqlmock.NewResult(2, 1))
+
+		item := &OrderItem{SKU: "b"}
+		if _, err := items.Create(context.Background(), item); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 4, item.OrderID)
+	})
+
+	t.Run("Test WithScope() Update and Delete", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE order_items SET order_id = ?, sku = ? WHERE id = ? AND order_id = ?")).
+			WithArgs(4, "c", 1, int64(4)).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM order_items WHERE id = ? AND order_id = ?")).
+			WithArgs(1, int64(4)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, items.Update(context.Background(), &OrderItem{OrderID: 9, SKU: "c"}, 1))
+		assert.NoError(t, items.Delete(context.Background(), 1))
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -3,162 +3,632 @@ package gocrud
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
 	"sync"
 
 	sq "github.com/Masterminds/squirrel"
 )
 
+var (
+	ErrSoftDeleteDisabled = errors.New("soft delete is not enabled for this repository")
+	ErrStaleObject        = errors.New("object was modified or deleted by another request")
+	ErrInvalidKey         = errors.New("key does not match the primary key columns")
+)
+
 type Model interface {
 	StructToMap(d interface{}) map[string]any
 }
 
-type Repository[M Model] struct {
-	mutex           sync.Mutex
+type Repository[M Model, K comparable] struct {
+	mutex           *sync.Mutex
 	db              *sql.DB
 	getConcreteType func() M
 	table           string
+	config          config
+	withDeleted     bool
+	relations       map[string]relation
+	preload         []string
+	scopes          sq.Eq
 }
 
-func NewGenericRepository[M Model](db *sql.DB, table string, callback func() M) *Repository[M] {
-	return &Repository[M]{
+// NewGenericRepository creates a repository for a table with an integer primary key.
+func NewGenericRepository[M Model](db *sql.DB, table string, callback func() M, opts ...Option) *Repository[M, int] {
+	return NewRepository[M, int](db, table, callback, opts...)
+}
+
+// NewRepository creates a repository for a table whose primary key has type K, e.g. int64, string or a UUID type.
+func NewRepository[M Model, K comparable](db *sql.DB, table string, callback func() M, opts ...Option) *Repository[M, K] {
+	r := &Repository[M, K]{
+		mutex:           &sync.Mutex{},
 		db:              db,
 		getConcreteType: callback,
 		table:           table,
+		relations:       make(map[string]relation),
+	}
+
+	for _, opt := range opts {
+		opt(&r.config)
+	}
+
+	if len(r.config.keyColumns) == 0 {
+		if keyed, ok := any(callback()).(KeyColumner); ok {
+			r.config.keyColumns = keyed.KeyColumns()
+		}
+	}
+	if len(r.config.keyColumns) == 0 {
+		r.config.keyColumns = []string{"id"}
+	}
+
+	if r.config.dialect.Placeholder == nil {
+		r.config.dialect = Default
+	}
+
+	return r
+}
+
+// WithDeleted returns a view of the repository whose Get and GetAll also return soft-deleted rows.
+func (r *Repository[M, K]) WithDeleted() *Repository[M, K] {
+	v := r.view()
+	v.withDeleted = true
+
+	return v
+}
+
+// view returns a copy of the repository sharing its mutex, table, configuration and relations.
+func (r *Repository[M, K]) view() *Repository[M, K] {
+	return &Repository[M, K]{
+		mutex:           r.mutex,
+		db:              r.db,
+		getConcreteType: r.getConcreteType,
+		table:           r.table,
+		config:          r.config,
+		withDeleted:     r.withDeleted,
+		relations:       r.relations,
+		preload:         r.preload,
+		scopes:          r.scopes,
 	}
 }
 
-func (r *Repository[M]) GetTable() string {
+// WithScope returns a view of the repository restricted to rows whose column holds value, e.g.
+// the children of one parent. Reads, updates and deletes only match such rows, and Create and
+// Update store value in the column.
+func (r *Repository[M, K]) WithScope(column string, value any) *Repository[M, K] {
+	v := r.view()
+	v.scopes = make(sq.Eq, len(r.scopes)+1)
+	maps.Copy(v.scopes, r.scopes)
+	v.scopes[column] = value
+
+	return v
+}
+
+func (r *Repository[M, K]) GetTable() string {
 	return r.table
 }
 
-func (r *Repository[M]) set(fields []string, scan func(dest ...any) error, model M) error {
-	validate := model.StructToMap(model)
+func (r *Repository[M, K]) fields(model M) map[string]any {
+	return r.config.fields(model)
+}
+
+func (r *Repository[M, K]) set(fields []string, scan func(dest ...any) error, model M) error {
+	return r.config.set(fields, scan, model)
+}
+
+// fields returns the model's column map with registered converters applied and slice fields
+// adapted to the dialect.
+func (c config) fields(model Model) map[string]any {
+	m := model.StructToMap(model)
+	for column, p := range m {
+		m[column] = adaptColumn(c.dialect, c.converters.convert(p))
+	}
+
+	return m
+}
+
+func (c config) set(fields []string, scan func(dest ...any) error, model Model) error {
+	validate := c.fields(model)
 
 	dest := make([]any, 0, len(fields))
 
 	for _, field := range fields {
 		if p, ok := validate[field]; ok {
 			dest = append(dest, p)
+			continue
 		}
+		// Columns the model does not map (e.g. deleted_at) are read and discarded.
+		dest = append(dest, new(any))
 	}
 
 	return scan(dest...)
 }
 
-func (r *Repository[M]) Create(ctx context.Context, model M) (int, error) {
+func (r *Repository[M, K]) builder() sq.StatementBuilderType {
+	return sq.StatementBuilder.PlaceholderFormat(r.config.dialect.Placeholder)
+}
+
+func (r *Repository[M, K]) scope(b sq.SelectBuilder) sq.SelectBuilder {
+	if len(r.scopes) > 0 {
+		b = b.Where(r.scopes)
+	}
+	if r.config.softDelete && !r.withDeleted {
+		return b.Where(sq.Eq{softDeleteColumn: nil})
+	}
+
+	return b
+}
+
+// matching adds the scope conditions to the key condition of a write.
+func (r *Repository[M, K]) matching(eq sq.Eq) sq.Eq {
+	if len(r.scopes) == 0 {
+		return eq
+	}
+
+	m := maps.Clone(eq)
+	maps.Copy(m, r.scopes)
+
+	return m
+}
+
+// values returns the column map of a model to be written, with the scope values stored in it.
+func (r *Repository[M, K]) values(model M) (map[string]any, error) {
+	m := r.fields(model)
+	for column, value := range r.scopes {
+		p, ok := m[column]
+		if !ok {
+			m[column] = value
+			continue
+		}
+
+		dst, v := fieldValue(p), reflect.ValueOf(value)
+		switch {
+		case !v.IsValid():
+			dst.SetZero()
+		case v.Type().AssignableTo(dst.Type()):
+			dst.Set(v)
+		case v.Type().ConvertibleTo(dst.Type()):
+			dst.Set(v.Convert(dst.Type()))
+		default:
+			return nil, fmt.Errorf("scope %s: cannot assign %T to %s", column, value, dst.Type())
+		}
+	}
+
+	return m, nil
+}
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (r *Repository[M, K]) exec(ctx context.Context, b sq.Sqlizer) (sql.Result, error) {
+	return execOn(ctx, r.db, b)
+}
+
+func execOn(ctx context.Context, q querier, b sq.Sqlizer) (sql.Result, error) {
+	query, args, err := b.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	return q.ExecContext(ctx, query, args...)
+}
+
+func (r *Repository[M, K]) composite() bool {
+	return len(r.config.keyColumns) > 1
+}
+
+// keyEq builds the WHERE condition matching the row with the given key.
+func (r *Repository[M, K]) keyEq(id K) (sq.Eq, error) {
+	if !r.composite() {
+		return sq.Eq{r.config.keyColumns[0]: id}, nil
+	}
+
+	key, ok := any(&id).(Model)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T must implement Model", ErrInvalidKey, id)
+	}
+
+	return columnsEq(r.config.keyColumns, key.StructToMap(key))
+}
+
+// keyFromModel reads the primary key columns of a model into a new key.
+func (r *Repository[M, K]) keyFromModel(m map[string]any) (K, error) {
+	var key K
+
+	if !r.composite() {
+		p, ok := m[r.config.keyColumns[0]]
+		if !ok {
+			return key, fmt.Errorf("%w: model has no %q field", ErrInvalidKey, r.config.keyColumns[0])
+		}
+		v, ok := fieldValue(p).Interface().(K)
+		if !ok {
+			return key, fmt.Errorf("%w: %q field is not a %T", ErrInvalidKey, r.config.keyColumns[0], key)
+		}
+		return v, nil
+	}
+
+	k, ok := any(&key).(Model)
+	if !ok {
+		return key, fmt.Errorf("%w: %T must implement Model", ErrInvalidKey, key)
+	}
+
+	km := k.StructToMap(k)
+	for _, column := range r.config.keyColumns {
+		dst, ok := km[column]
+		src, found := m[column]
+		if !ok || !found {
+			return key, fmt.Errorf("%w: missing %q", ErrInvalidKey, column)
+		}
+		d, v := fieldValue(dst), fieldValue(src)
+		switch {
+		case v.Type().AssignableTo(d.Type()):
+			d.Set(v)
+		// Integers convert to strings as runes, never a valid key.
+		case v.Type().ConvertibleTo(d.Type()) && (d.Kind() != reflect.String || v.Kind() == reflect.String):
+			d.Set(v.Convert(d.Type()))
+		default:
+			return key, fmt.Errorf("%w: %q field is not a %s", ErrInvalidKey, column, d.Type())
+		}
+	}
+
+	return key, nil
+}
+
+func columnsEq(columns []string, m map[string]any) (sq.Eq, error) {
+	eq := make(sq.Eq, len(columns))
+	for _, column := range columns {
+		p, ok := m[column]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing %q", ErrInvalidKey, column)
+		}
+		eq[column] = fieldValue(p).Interface()
+	}
+
+	return eq, nil
+}
+
+func checkAffected(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *Repository[M, K]) Create(ctx context.Context, model M) (K, error) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	m := model.StructToMap(model)
+	return r.create(ctx, r.db, model)
+}
+
+// CreateReturning inserts the model and populates it with the stored row, including
+// database defaults. Dialects without RETURNING use a follow-up select by key.
+func (r *Repository[M, K]) CreateReturning(ctx context.Context, model M) (M, error) {
+	var zero M
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.config.dialect.Returning {
+		m, err := r.values(model)
+		if err != nil {
+			return zero, err
+		}
+
+		b, _, err := r.insert(m)
+		if err != nil {
+			return zero, err
+		}
+
+		if err := r.queryOne(ctx, b.Suffix("RETURNING *"), model); err != nil {
+			return zero, err
+		}
+
+		return model, nil
+	}
+
+	id, err := r.create(ctx, r.db, model)
+	if err != nil {
+		return zero, err
+	}
+
+	eq, err := r.keyEq(id)
+	if err != nil {
+		return zero, err
+	}
+
+	if err := r.queryOne(ctx, r.builder().Select("*").From(r.table).Where(eq).Limit(1), model); err != nil {
+		return zero, err
+	}
+
+	return model, nil
+}
+
+// insert builds the INSERT for a model, returning the key column left to the database, if any.
+func (r *Repository[M, K]) insert(m map[string]any) (sq.InsertBuilder, string, error) {
+	if r.config.idStrategy == GeneratedID && !r.composite() {
+		if err := r.assignID(m); err != nil {
+			return sq.InsertBuilder{}, "", err
+		}
+	}
+
+	// Only a database-generated key is left out of the INSERT and read back afterwards.
+	generated := ""
+	if r.config.idStrategy == DatabaseID && !r.composite() {
+		generated = r.config.keyColumns[0]
+	}
 
 	columns := make([]string, 0, len(m))
 	values := make([]any, 0, len(m))
 
 	for key, value := range m {
-		if key == "id" {
+		if key == generated || (r.config.softDelete && key == softDeleteColumn) {
 			continue
 		}
 		columns = append(columns, key)
 		values = append(values, value)
 	}
 
-	query, args, err := sq.
+	b := r.builder().
 		Insert(r.table).
 		Columns(columns...).
-		Values(values...).
-		ToSql()
+		Values(values...)
+
+	return b, generated, nil
+}
+
+func (r *Repository[M, K]) create(ctx context.Context, q querier, model M) (K, error) {
+	var zero K
+
+	m, err := r.values(model)
+	if err != nil {
+		return zero, err
+	}
+
+	b, generated, err := r.insert(m)
 	if err != nil {
-		return 0, err
+		return zero, err
+	}
+
+	if generated == "" {
+		if _, err := execOn(ctx, q, b); err != nil {
+			return zero, err
+		}
+		return r.keyFromModel(m)
+	}
+
+	// The generated key is read back with RETURNING where the dialect supports it, as drivers
+	// such as lib/pq do not implement LastInsertId. Otherwise only integer keys can be read.
+	if r.config.dialect.Returning {
+		query, args, err := b.Suffix("RETURNING " + generated).ToSql()
+		if err != nil {
+			return zero, err
+		}
+
+		var id K
+		if err := q.QueryRowContext(ctx, query, args...).Scan(&id); err != nil {
+			return zero, err
+		}
+
+		return id, nil
+	}
+
+	if !isIntegerKey[K]() {
+		return zero, fmt.Errorf("%w: %s cannot return a generated %T key", ErrInvalidKey, r.config.dialect.Name, zero)
 	}
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+	result, err := execOn(ctx, q, b)
 	if err != nil {
-		return 0, err
+		return zero, err
 	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
-		return 0, err
+		return zero, err
+	}
+
+	return intToKey[K](id), nil
+}
+
+// assignID sets the model's key field to a value from the configured generator.
+func (r *Repository[M, K]) assignID(m map[string]any) error {
+	column := r.config.keyColumns[0]
+	if r.config.newID == nil {
+		return fmt.Errorf("%w: no id generator configured", ErrInvalidKey)
+	}
+
+	p, ok := m[column]
+	if !ok {
+		return fmt.Errorf("%w: model has no %q field", ErrInvalidKey, column)
+	}
+
+	dst := fieldValue(p)
+	id := reflect.ValueOf(r.config.newID())
+	if !id.Type().AssignableTo(dst.Type()) {
+		return fmt.Errorf("%w: generated %s is not assignable to %s", ErrInvalidKey, id.Type(), dst.Type())
+	}
+	dst.Set(id)
+
+	return nil
+}
+
+func isIntegerKey[K comparable]() bool {
+	switch reflect.TypeFor[K]().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+func intToKey[K comparable](id int64) K {
+	var key K
+
+	v := reflect.ValueOf(&key).Elem()
+	if v.CanInt() {
+		v.SetInt(id)
+	} else {
+		v.SetUint(uint64(id))
+	}
+
+	return key
+}
+
+func (r *Repository[M, K]) Get(ctx context.Context, id K) (M, error) {
+	eq, err := r.keyEq(id)
+	if err != nil {
+		var zero M
+		return zero, err
+	}
+
+	return r.get(ctx, eq)
+}
+
+// GetByKey looks a row up by its primary key column values, in the order given to WithPrimaryKey.
+func (r *Repository[M, K]) GetByKey(ctx context.Context, values ...any) (M, error) {
+	if len(values) != len(r.config.keyColumns) {
+		var zero M
+		return zero, fmt.Errorf("%w: got %d values for %d columns", ErrInvalidKey, len(values), len(r.config.keyColumns))
+	}
+
+	eq := make(sq.Eq, len(values))
+	for i, column := range r.config.keyColumns {
+		eq[column] = values[i]
+	}
+
+	return r.get(ctx, eq)
+}
+
+func (r *Repository[M, K]) get(ctx context.Context, eq sq.Eq) (M, error) {
+	var zero M
+
+	model, err := r.one(ctx, eq)
+	if err != nil {
+		return zero, err
+	}
+
+	if err := r.preloadInto(ctx, []M{model}); err != nil {
+		return zero, err
 	}
 
-	return int(id), nil
+	return model, nil
 }
 
-func (r *Repository[M]) Get(ctx context.Context, id int) (M, error) {
+func (r *Repository[M, K]) one(ctx context.Context, eq sq.Eq) (M, error) {
 	var zero M
 
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	query, args, err := sq.
+	model := r.getConcreteType()
+	if err := r.queryOne(ctx, r.scope(r.builder().
 		Select("*").
 		From(r.table).
-		Where(sq.Eq{"id": id}).
-		Limit(1).
-		ToSql()
-	if err != nil {
+		Where(eq)).
+		Limit(1), model); err != nil {
 		return zero, err
 	}
 
+	return model, nil
+}
+
+// queryOne scans the first row returned by b into model, or returns sql.ErrNoRows.
+func (r *Repository[M, K]) queryOne(ctx context.Context, b sq.Sqlizer, model M) error {
+	query, args, err := b.ToSql()
+	if err != nil {
+		return err
+	}
+
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return zero, err
+		return err
 	}
+	defer rows.Close()
+
 	if !rows.Next() {
 		if err := rows.Err(); err != nil {
-			return zero, err
+			return err
 		}
-		return zero, err
+		if err := rows.Close(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
 	}
 
 	fields, err := rows.Columns()
 	if err != nil {
-		return zero, err
+		return err
 	}
 
-	model := r.getConcreteType()
 	if err := r.set(fields, rows.Scan, model); err != nil {
-		return zero, err
+		return err
 	}
 
 	if err = rows.Close(); err != nil {
-		return zero, err
+		return err
 	}
 
-	if err = rows.Err(); err != nil {
-		return zero, err
+	return rows.Err()
+}
+
+func (r *Repository[M, K]) GetAll(ctx context.Context) ([]M, error) {
+	models, err := r.list(ctx, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	return model, nil
+	if err := r.preloadInto(ctx, models); err != nil {
+		return nil, err
+	}
+
+	return models, nil
 }
 
-func (r *Repository[M]) GetAll(ctx context.Context) ([]M, error) {
+// list returns the rows matching where, or all rows if it is nil, ordered by primary key.
+func (r *Repository[M, K]) list(ctx context.Context, where sq.Sqlizer) ([]M, error) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	query, args, err := sq.
-		Select("*").
-		From(r.table).
-		OrderBy("id").ToSql()
+	b := r.scope(r.builder().Select("*").From(r.table))
+	if where != nil {
+		b = b.Where(where)
+	}
+
+	return queryAll(ctx, r.db, r.config, b.OrderBy(r.config.keyColumns...), r.getConcreteType)
+}
+
+// queryAll scans every row returned by b into a model created by newModel.
+func queryAll[T Model](ctx context.Context, db *sql.DB, c config, b sq.Sqlizer, newModel func() T) ([]T, error) {
+	query, args, err := b.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	fields, err := rows.Columns()
 	if err != nil {
 		return nil, err
 	}
 
-	var models []M
+	var models []T
 	for rows.Next() {
-		model := r.getConcreteType()
+		model := newModel()
 
-		if err := r.set(fields, rows.Scan, model); err != nil {
+		if err := c.set(fields, rows.Scan, model); err != nil {
 			return nil, err
 		}
 
@@ -176,36 +646,157 @@ func (r *Repository[M]) GetAll(ctx context.Context) ([]M, error) {
 	return models, nil
 }
 
-func (r *Repository[M]) Delete(ctx context.Context, id int) error {
+func (r *Repository[M, K]) Delete(ctx context.Context, id K) error {
+	if !r.config.softDelete {
+		return r.HardDelete(ctx, id)
+	}
+
+	eq, err := r.keyEq(id)
+	if err != nil {
+		return err
+	}
+
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	query, args, err := sq.Delete(r.table).Where(sq.Eq{"id": id}).ToSql()
+	result, err := r.exec(ctx, r.builder().Update(r.table).
+		Set(softDeleteColumn, sq.Expr("CURRENT_TIMESTAMP")).
+		Where(r.matching(eq)).
+		Where(sq.Eq{softDeleteColumn: nil}))
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(result)
+}
+
+// HardDelete physically removes the row, regardless of the soft delete mode.
+func (r *Repository[M, K]) HardDelete(ctx context.Context, id K) error {
+	eq, err := r.keyEq(id)
 	if err != nil {
 		return err
 	}
 
-	_, err = r.db.ExecContext(ctx, query, args...)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	_, err = r.exec(ctx, r.builder().Delete(r.table).Where(r.matching(eq)))
 
 	return err
 }
 
-func (r *Repository[M]) Update(ctx context.Context, model M, id int) error {
+// Restore clears deleted_at on a soft-deleted row. It returns sql.ErrNoRows if no such row exists.
+func (r *Repository[M, K]) Restore(ctx context.Context, id K) error {
+	if !r.config.softDelete {
+		return ErrSoftDeleteDisabled
+	}
+
+	eq, err := r.keyEq(id)
+	if err != nil {
+		return err
+	}
+
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	m := model.StructToMap(model)
-	delete(m, "id")
+	result, err := r.exec(ctx, r.builder().Update(r.table).
+		Set(softDeleteColumn, nil).
+		Where(r.matching(eq)).
+		Where(sq.NotEq{softDeleteColumn: nil}))
+	if err != nil {
+		return err
+	}
+
+	return checkAffected(result)
+}
+
+func (r *Repository[M, K]) Update(ctx context.Context, model M, id K) error {
+	eq, err := r.keyEq(id)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	m, err := r.values(model)
+	if err != nil {
+		return err
+	}
+	for _, column := range r.config.keyColumns {
+		delete(m, column)
+	}
 
-	query, args, err := sq.Update(r.table).
+	b := r.builder().Update(r.table).Where(r.matching(eq))
+	if r.config.softDelete {
+		delete(m, softDeleteColumn)
+		b = b.Where(sq.Eq{softDeleteColumn: nil})
+	}
+
+	column := r.config.versionColumn
+	if column == "" {
+		_, err = r.exec(ctx, b.SetMap(m))
+		return err
+	}
+
+	version, ok := m[column]
+	if !ok {
+		return fmt.Errorf("model has no %q field", column)
+	}
+	delete(m, column)
+
+	result, err := r.exec(ctx, b.
 		SetMap(m).
-		Where(sq.Eq{"id": id}).
-		ToSql()
+		Set(column, sq.Expr(column+" + 1")).
+		Where(sq.Eq{column: fieldValue(version).Interface()}))
+	if err != nil {
+		return err
+	}
+
+	if err := checkAffected(result); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return r.staleOrMissing(ctx, eq)
+		}
+		return err
+	}
+
+	incrementVersion(version)
+
+	return nil
+}
+
+// staleOrMissing explains a versioned update that matched no row: ErrStaleObject if the row
+// still exists, sql.ErrNoRows if it does not.
+func (r *Repository[M, K]) staleOrMissing(ctx context.Context, eq sq.Eq) error {
+	b := r.builder().Select("1").From(r.table).Where(r.matching(eq))
+	if r.config.softDelete {
+		b = b.Where(sq.Eq{softDeleteColumn: nil})
+	}
+
+	query, args, err := b.Limit(1).ToSql()
 	if err != nil {
 		return err
 	}
 
-	_, err = r.db.ExecContext(ctx, query, args...)
+	var found int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&found); err != nil {
+		return err
+	}
 
-	return err
+	return ErrStaleObject
+}
+
+func incrementVersion(p any) {
+	v := fieldValue(p)
+	if !v.CanSet() {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(v.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(v.Uint() + 1)
+	default:
+	}
 }
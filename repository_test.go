@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
 	"regexp"
 	"testing"
 
@@ -304,6 +305,23 @@ func TestGenericRepository_Create(t *testing.T) {
 			t.Errorf("there were unfulfilled expectations: %s", err)
 		}
 	})
+
+	t.Run("Test Generic Repository: Create() - RETURNING on Postgres", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO table_name \(((ip)(,)?|(name)(,)?|(type)(,)?|(chip)(,)?|(board)(,)?){5}\) VALUES \(\$1,\$2,\$3,\$4,\$5\) RETURNING id`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+
+		repo := NewGenericRepository(db, "table_name", func() *ModelWithReflection { return &ModelWithReflection{} }, WithDialect(Postgres))
+		got, err := repo.Create(context.Background(), &ModelWithReflection{Name: "test 1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 9, got)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
 }
 
 func TestGenericRepository_Update(t *testing.T) {
@@ -364,3 +382,691 @@ func TestGenericRepository_Update(t *testing.T) {
 		}
 	})
 }
+
+func TestGenericRepository_SoftDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewGenericRepository(db, "table_name", func() *ModelWithReflection { return &ModelWithReflection{} }, WithSoftDelete())
+
+	t.Run("Test Generic Repository: Get() excludes soft-deleted rows", func(t *testing.T) {
+		want := &ModelWithReflection{ID: 1, Name: "test 1"}
+
+		rows := sqlmock.NewRows([]string{"id", "name", "deleted_at"}).
+			AddRow(want.ID, want.Name, nil)
+		query := regexp.QuoteMeta("SELECT * FROM table_name WHERE id = ? AND deleted_at IS NULL LIMIT 1")
+		mock.ExpectQuery(query).WithArgs(want.ID).WillReturnRows(rows)
+
+		got, err := repo.Get(context.Background(), want.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("Test Generic Repository: Get() soft-deleted row", func(t *testing.T) {
+		query := regexp.QuoteMeta("SELECT * FROM table_name WHERE id = ? AND deleted_at IS NULL LIMIT 1")
+		mock.ExpectQuery(query).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deleted_at"}))
+
+		_, err := repo.Get(context.Background(), 2)
+		assert.Equal(t, sql.ErrNoRows, err)
+	})
+
+	t.Run("Test Generic Repository: GetAll() excludes soft-deleted rows", func(t *testing.T) {
+		query := regexp.QuoteMeta("SELECT * FROM table_name WHERE deleted_at IS NULL ORDER BY id")
+		mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deleted_at"}).AddRow(1, "test 1", nil))
+
+		got, err := repo.GetAll(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []*ModelWithReflection{{ID: 1, Name: "test 1"}}, got)
+	})
+
+	t.Run("Test Generic Repository: WithDeleted().GetAll()", func(t *testing.T) {
+		query := regexp.QuoteMeta("SELECT * FROM table_name ORDER BY id")
+		mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deleted_at"}).AddRow(1, "test 1", "2025-01-01"))
+
+		got, err := repo.WithDeleted().GetAll(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, []*ModelWithReflection{{ID: 1, Name: "test 1"}}, got)
+	})
+
+	t.Run("Test Generic Repository: Delete()", func(t *testing.T) {
+		query := regexp.QuoteMeta("UPDATE table_name SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL")
+		mock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.Delete(context.Background(), 1))
+	})
+
+	t.Run("Test Generic Repository: Delete() already deleted", func(t *testing.T) {
+		query := regexp.QuoteMeta("UPDATE table_name SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL")
+		mock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.Equal(t, sql.ErrNoRows, repo.Delete(context.Background(), 1))
+	})
+
+	t.Run("Test Generic Repository: Restore()", func(t *testing.T) {
+		query := regexp.QuoteMeta("UPDATE table_name SET deleted_at = ? WHERE id = ? AND deleted_at IS NOT NULL")
+		mock.ExpectExec(query).WithArgs(nil, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.Restore(context.Background(), 1))
+	})
+
+	t.Run("Test Generic Repository: HardDelete()", func(t *testing.T) {
+		query := regexp.QuoteMeta("DELETE FROM table_name WHERE id = ?")
+		mock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.HardDelete(context.Background(), 1))
+	})
+
+	t.Run("Test Generic Repository: Restore() without soft delete", func(t *testing.T) {
+		repo := NewGenericRepository(db, "table_name", func() *ModelWithReflection { return &ModelWithReflection{} })
+
+		assert.Equal(t, ErrSoftDeleteDisabled, repo.Restore(context.Background(), 1))
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+type ModelWithVersion struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+	Reflection
+}
+
+func TestGenericRepository_UpdateVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewGenericRepository(db, "table_name", func() *ModelWithVersion { return &ModelWithVersion{} }, WithVersionColumn("version"))
+
+	t.Run("Test Generic Repository: Update() increments version", func(t *testing.T) {
+		model := &ModelWithVersion{Name: "test 1", Version: 3}
+
+		query := regexp.QuoteMeta("UPDATE table_name SET name = ?, version = version + 1 WHERE id = ? AND version = ?")
+		mock.ExpectExec(query).WithArgs("test 1", 1, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		if err := repo.Update(context.Background(), model, 1); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 4, model.Version)
+	})
+
+	t.Run("Test Generic Repository: Update() stale version", func(t *testing.T) {
+		model := &ModelWithVersion{Name: "test 1", Version: 3}
+
+		query := regexp.QuoteMeta("UPDATE table_name SET name = ?, version = version + 1 WHERE id = ? AND version = ?")
+		mock.ExpectExec(query).WithArgs("test 1", 1, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM table_name WHERE id = ? LIMIT 1")).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+		err := repo.Update(context.Background(), model, 1)
+
+		assert.Equal(t, ErrStaleObject, err)
+		assert.Equal(t, 3, model.Version)
+	})
+
+	t.Run("Test Generic Repository: Update() missing row", func(t *testing.T) {
+		model := &ModelWithVersion{Name: "test 1", Version: 3}
+
+		query := regexp.QuoteMeta("UPDATE table_name SET name = ?, version = version + 1 WHERE id = ? AND version = ?")
+		mock.ExpectExec(query).WithArgs("test 1", 9, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM table_name WHERE id = ? LIMIT 1")).WithArgs(9).
+			WillReturnRows(sqlmock.NewRows([]string{"1"}))
+
+		err := repo.Update(context.Background(), model, 9)
+
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("Test Generic Repository: Update() soft-deleted row", func(t *testing.T) {
+		repo := NewGenericRepository(db, "table_name", func() *ModelWithVersion { return &ModelWithVersion{} },
+			WithVersionColumn("version"), WithSoftDelete())
+		model := &ModelWithVersion{Name: "test 1", Version: 3}
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE table_name SET name = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL AND version = ?")).
+			WithArgs("test 1", 1, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM table_name WHERE id = ? AND deleted_at IS NULL LIMIT 1")).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"1"}))
+
+		err := repo.Update(context.Background(), model, 1)
+
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+type ModelWithStringKey struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Reflection
+}
+
+func TestGenericRepository_StringKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewRepository[*ModelWithStringKey, string](db, "table_name", func() *ModelWithStringKey { return &ModelWithStringKey{} })
+
+	t.Run("Test Generic Repository: Create() with string key", func(t *testing.T) {
+		repo := NewRepository[*ModelWithStringKey, string](db, "table_name", func() *ModelWithStringKey { return &ModelWithStringKey{} },
+			WithDialect(Postgres))
+
+		query := regexp.QuoteMeta("INSERT INTO table_name (name) VALUES ($1) RETURNING id")
+		mock.ExpectQuery(query).WithArgs("test 1").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("d7e949b8-5c41-4972-b484-9c33b89af32c"))
+
+		got, err := repo.Create(context.Background(), &ModelWithStringKey{Name: "test 1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "d7e949b8-5c41-4972-b484-9c33b89af32c", got)
+	})
+
+	t.Run("Test Generic Repository: Create() with string key without RETURNING", func(t *testing.T) {
+		_, err := repo.Create(context.Background(), &ModelWithStringKey{Name: "test 1"})
+
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+
+	t.Run("Test Generic Repository: Get() with string key", func(t *testing.T) {
+		want := &ModelWithStringKey{ID: "d7e949b8-5c41-4972-b484-9c33b89af32c", Name: "test 1"}
+
+		query := regexp.QuoteMeta("SELECT * FROM table_name WHERE id = ? LIMIT 1")
+		mock.ExpectQuery(query).WithArgs(want.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(want.ID, want.Name))
+
+		got, err := repo.Get(context.Background(), want.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("Test Generic Repository: Create() with int64 key", func(t *testing.T) {
+		repo := NewRepository[*ModelWithReflection, int64](db, "table_name", func() *ModelWithReflection { return &ModelWithReflection{} })
+
+		mock.ExpectExec("INSERT INTO table_name").WillReturnResult(sqlmock.NewResult(1790478563172503552, 1))
+
+		got, err := repo.Create(context.Background(), &ModelWithReflection{Name: "test 1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, int64(1790478563172503552), got)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+type SKUKey struct {
+	TenantID int    `db:"tenant_id"`
+	SKU      string `db:"sku"`
+	Reflection
+}
+
+type Product struct {
+	TenantID int    `db:"tenant_id" json:"tenant_id"`
+	SKU      string `db:"sku" json:"sku"`
+	Name     string `json:"name"`
+	Reflection
+}
+
+type wideSKUKey struct {
+	TenantID int64  `db:"tenant_id"`
+	SKU      string `db:"sku"`
+	Reflection
+}
+
+type numericSKUKey struct {
+	TenantID int `db:"tenant_id"`
+	SKU      int `db:"sku"`
+	Reflection
+}
+
+// KeyedProduct names its key columns like a model generated by gocrud-gen.
+type KeyedProduct struct {
+	Product
+}
+
+func (*KeyedProduct) KeyColumns() []string {
+	return []string{"tenant_id", "sku"}
+}
+
+func TestGenericRepository_PrimaryKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	t.Run("Test Generic Repository: Get() with custom key column", func(t *testing.T) {
+		repo := NewGenericRepository(db, "users", func() *ModelWithReflection { return &ModelWithReflection{} }, WithPrimaryKey("user_id"))
+
+		query := regexp.QuoteMeta("SELECT * FROM users WHERE user_id = ? LIMIT 1")
+		mock.ExpectQuery(query).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"user_id", "name"}).AddRow(1, "test 1"))
+
+		got, err := repo.Get(context.Background(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "test 1", got.Name)
+	})
+
+	t.Run("Test Generic Repository: GetAll() with custom key column", func(t *testing.T) {
+		repo := NewGenericRepository(db, "users", func() *ModelWithReflection { return &ModelWithReflection{} }, WithPrimaryKey("user_id"))
+
+		query := regexp.QuoteMeta("SELECT * FROM users ORDER BY user_id")
+		mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"user_id", "name"}))
+
+		_, err := repo.GetAll(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Test Generic Repository: key columns from the model", func(t *testing.T) {
+		repo := NewRepository[*KeyedProduct, SKUKey](db, "products", func() *KeyedProduct { return &KeyedProduct{} })
+
+		assert.Equal(t, []string{"tenant_id", "sku"}, repo.config.keyColumns)
+	})
+
+	repo := NewRepository[*Product, SKUKey](db, "products", func() *Product { return &Product{} }, WithPrimaryKey("tenant_id", "sku"))
+
+	t.Run("Test Generic Repository: Create() with composite key", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO products \(((tenant_id)(,)?|(sku)(,)?|(name)(,)?){3}\) VALUES \(([?,]+)\)`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		got, err := repo.Create(context.Background(), &Product{TenantID: 7, SKU: "AB-1", Name: "test 1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, SKUKey{TenantID: 7, SKU: "AB-1"}, got)
+	})
+
+	t.Run("Test Generic Repository: Create() with composite key of other field types", func(t *testing.T) {
+		repo := NewRepository[*Product, wideSKUKey](db, "products", func() *Product { return &Product{} }, WithPrimaryKey("tenant_id", "sku"))
+		mock.ExpectExec(`INSERT INTO products`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		got, err := repo.Create(context.Background(), &Product{TenantID: 7, SKU: "AB-1", Name: "test 1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, wideSKUKey{TenantID: 7, SKU: "AB-1"}, got)
+	})
+
+	t.Run("Test Generic Repository: Create() with mismatched composite key", func(t *testing.T) {
+		repo := NewRepository[*Product, numericSKUKey](db, "products", func() *Product { return &Product{} }, WithPrimaryKey("tenant_id", "sku"))
+		mock.ExpectExec(`INSERT INTO products`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		_, err := repo.Create(context.Background(), &Product{TenantID: 7, SKU: "AB-1", Name: "test 1"})
+
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+
+	t.Run("Test Generic Repository: Get() with composite key", func(t *testing.T) {
+		want := &Product{TenantID: 7, SKU: "AB-1", Name: "test 1"}
+
+		query := regexp.QuoteMeta("SELECT * FROM products WHERE sku = ? AND tenant_id = ? LIMIT 1")
+		mock.ExpectQuery(query).WithArgs("AB-1", 7).
+			WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "sku", "name"}).AddRow(7, "AB-1", "test 1"))
+
+		got, err := repo.Get(context.Background(), SKUKey{TenantID: 7, SKU: "AB-1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("Test Generic Repository: GetByKey() with composite key", func(t *testing.T) {
+		query := regexp.QuoteMeta("SELECT * FROM products WHERE sku = ? AND tenant_id = ? LIMIT 1")
+		mock.ExpectQuery(query).WithArgs("AB-1", 7).
+			WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "sku", "name"}).AddRow(7, "AB-1", "test 1"))
+
+		got, err := repo.GetByKey(context.Background(), 7, "AB-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "test 1", got.Name)
+	})
+
+	t.Run("Test Generic Repository: GetByKey() wrong number of values", func(t *testing.T) {
+		_, err := repo.GetByKey(context.Background(), 7)
+
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+
+	t.Run("Test Generic Repository: Update() with composite key", func(t *testing.T) {
+		query := regexp.QuoteMeta("UPDATE products SET name = ? WHERE sku = ? AND tenant_id = ?")
+		mock.ExpectExec(query).WithArgs("test 2", "AB-1", 7).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Update(context.Background(), &Product{Name: "test 2"}, SKUKey{TenantID: 7, SKU: "AB-1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Test Generic Repository: Delete() with composite key", func(t *testing.T) {
+		query := regexp.QuoteMeta("DELETE FROM products WHERE sku = ? AND tenant_id = ?")
+		mock.ExpectExec(query).WithArgs("AB-1", 7).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Delete(context.Background(), SKUKey{TenantID: 7, SKU: "AB-1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGenericRepository_IDStrategy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	t.Run("Test Generic Repository: Create() with generated id", func(t *testing.T) {
+		repo := NewRepository[*ModelWithStringKey, string](db, "table_name", func() *ModelWithStringKey { return &ModelWithStringKey{} },
+			WithIDGenerator(func() string { return "0192f0c4-7b1e-7c4e-9f3a-5d2c1b0a9e8f" }))
+
+		mock.ExpectExec(`INSERT INTO table_name \(((id)(,)?|(name)(,)?){2}\) VALUES \(([?,]+)\)`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		model := &ModelWithStringKey{Name: "test 1"}
+		got, err := repo.Create(context.Background(), model)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "0192f0c4-7b1e-7c4e-9f3a-5d2c1b0a9e8f", got)
+		assert.Equal(t, "0192f0c4-7b1e-7c4e-9f3a-5d2c1b0a9e8f", model.ID)
+	})
+
+	t.Run("Test Generic Repository: Create() with supplied id", func(t *testing.T) {
+		repo := NewGenericRepository(db, "table_name", func() *ModelWithReflection { return &ModelWithReflection{} }, WithIDStrategy(SuppliedID))
+
+		mock.ExpectExec(`INSERT INTO table_name \(((id)(,)?|(ip)(,)?|(name)(,)?|(type)(,)?|(chip)(,)?|(board)(,)?){6}\) VALUES \(([?,]+)\)`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		got, err := repo.Create(context.Background(), &ModelWithReflection{ID: 42, Name: "test 1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 42, got)
+	})
+
+	t.Run("Test Generic Repository: Create() with generated id of wrong type", func(t *testing.T) {
+		repo := NewGenericRepository(db, "table_name", func() *ModelWithReflection { return &ModelWithReflection{} },
+			WithIDGenerator(func() string { return "abc" }))
+
+		_, err := repo.Create(context.Background(), &ModelWithReflection{Name: "test 1"})
+
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGenericRepository_CreateReturning(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	t.Run("Test Generic Repository: CreateReturning() with RETURNING", func(t *testing.T) {
+		repo := NewGenericRepository(db, "table_name", func() *ModelWithVersion { return &ModelWithVersion{} }, WithDialect(Postgres))
+
+		query := `INSERT INTO table_name \(((name)(,)?|(version)(,)?){2}\) VALUES \(\$1,\$2\) RETURNING \*`
+		mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "version"}).AddRow(5, "test 1", 1))
+
+		model := &ModelWithVersion{Name: "test 1"}
+		got, err := repo.CreateReturning(context.Background(), model)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, &ModelWithVersion{ID: 5, Name: "test 1", Version: 1}, got)
+		assert.Same(t, model, got)
+	})
+
+	t.Run("Test Generic Repository: CreateReturning() without RETURNING", func(t *testing.T) {
+		repo := NewGenericRepository(db, "table_name", func() *ModelWithVersion { return &ModelWithVersion{} }, WithDialect(MySQL))
+
+		mock.ExpectExec(`INSERT INTO table_name`).WillReturnResult(sqlmock.NewResult(6, 1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM table_name WHERE id = ? LIMIT 1")).WithArgs(6).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "version"}).AddRow(6, "test 1", 1))
+
+		got, err := repo.CreateReturning(context.Background(), &ModelWithVersion{Name: "test 1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, &ModelWithVersion{ID: 6, Name: "test 1", Version: 1}, got)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+type ModelWithNulls struct {
+	ID       int              `json:"id"`
+	Nickname *string          `json:"nickname"`
+	Email    sql.NullString   `json:"-"`
+	Phone    Null[string]     `json:"phone"`
+	Board    string           `json:"board" db:"board,nullzero"`
+	Score    int              `json:"score" db:"score,nullzero"`
+	Tags     Null[[]byte]     `json:"-"`
+	Extra    map[string]int64 `json:"-" db:"-"`
+	Reflection
+}
+
+func TestGenericRepository_Nulls(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewGenericRepository(db, "table_name", func() *ModelWithNulls { return &ModelWithNulls{} })
+
+	t.Run("Test Generic Repository: Get() with NULL columns", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "nickname", "email", "phone", "board", "score", "tags"}).
+			AddRow(1, nil, nil, nil, nil, nil, nil)
+		query := regexp.QuoteMeta("SELECT * FROM table_name WHERE id = ? LIMIT 1")
+		mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+
+		got, err := repo.Get(context.Background(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, &ModelWithNulls{ID: 1}, got)
+
+		body, err := json.Marshal(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.JSONEq(t, `{"id": 1, "nickname": null, "phone": null, "board": "", "score": 0}`, string(body))
+	})
+
+	t.Run("Test Generic Repository: Get() with non-NULL columns", func(t *testing.T) {
+		nickname := "nick"
+		rows := sqlmock.NewRows([]string{"id", "nickname", "email", "phone", "board", "score", "tags"}).
+			AddRow(1, nickname, "a@b.c", "123", "esp32", int64(7), []byte("x"))
+		query := regexp.QuoteMeta("SELECT * FROM table_name WHERE id = ? LIMIT 1")
+		mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+
+		got, err := repo.Get(context.Background(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, &ModelWithNulls{
+			ID:       1,
+			Nickname: &nickname,
+			Email:    sql.NullString{String: "a@b.c", Valid: true},
+			Phone:    NewNull("123"),
+			Board:    "esp32",
+			Score:    7,
+			Tags:     NewNull([]byte("x")),
+		}, got)
+	})
+
+	t.Run("Test Generic Repository: Update() with NULL values", func(t *testing.T) {
+		query := regexp.QuoteMeta("UPDATE table_name SET board = ?, email = ?, nickname = ?, phone = ?, score = ?, tags = ? WHERE id = ?")
+		mock.ExpectExec(query).WithArgs("", nil, nil, nil, 0, nil, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Update(context.Background(), &ModelWithNulls{}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+type ModelWithJSON struct {
+	ID       int               `json:"id"`
+	Settings settings          `json:"settings" db:"settings,json"`
+	Labels   map[string]string `json:"labels" db:"labels,json"`
+	Reflection
+}
+
+func TestGenericRepository_JSON(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewGenericRepository(db, "table_name", func() *ModelWithJSON { return &ModelWithJSON{} })
+
+	t.Run("Test Generic Repository: Get() with JSON columns", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "settings", "labels"}).
+			AddRow(1, []byte(`{"theme":"dark","beta":true}`), nil)
+		query := regexp.QuoteMeta("SELECT * FROM table_name WHERE id = ? LIMIT 1")
+		mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+
+		got, err := repo.Get(context.Background(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, &ModelWithJSON{ID: 1, Settings: settings{Theme: "dark", Beta: true}}, got)
+	})
+
+	t.Run("Test Generic Repository: Update() with JSON columns", func(t *testing.T) {
+		query := regexp.QuoteMeta("UPDATE table_name SET labels = ?, settings = ? WHERE id = ?")
+		mock.ExpectExec(query).WithArgs(`{"env":"prod"}`, `{"theme":"light","beta":false}`, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Update(context.Background(), &ModelWithJSON{
+			Settings: settings{Theme: "light"},
+			Labels:   map[string]string{"env": "prod"},
+		}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+type ModelWithArray struct {
+	ID      int      `json:"id"`
+	Actions []string `json:"actions"`
+	Reflection
+}
+
+func TestGenericRepository_Arrays(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	t.Run("Test Generic Repository: Get() with Postgres array", func(t *testing.T) {
+		repo := NewGenericRepository(db, "table_name", func() *ModelWithArray { return &ModelWithArray{} }, WithDialect(Postgres))
+
+		rows := sqlmock.NewRows([]string{"id", "actions"}).AddRow(1, []byte("{a,b}"))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM table_name WHERE id = $1 LIMIT 1")).WithArgs(1).WillReturnRows(rows)
+
+		got, err := repo.Get(context.Background(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, &ModelWithArray{ID: 1, Actions: []string{"a", "b"}}, got)
+	})
+
+	t.Run("Test Generic Repository: Update() with Postgres array", func(t *testing.T) {
+		repo := NewGenericRepository(db, "table_name", func() *ModelWithArray { return &ModelWithArray{} }, WithDialect(Postgres))
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE table_name SET actions = $1 WHERE id = $2")).WithArgs(`{"a","b"}`, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Update(context.Background(), &ModelWithArray{Actions: []string{"a", "b"}}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Test Generic Repository: Update() with JSON array", func(t *testing.T) {
+		repo := NewGenericRepository(db, "table_name", func() *ModelWithArray { return &ModelWithArray{} }, WithDialect(SQLite))
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE table_name SET actions = ? WHERE id = ?")).WithArgs(`["a","b"]`, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.Update(context.Background(), &ModelWithArray{Actions: []string{"a", "b"}}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
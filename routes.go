@@ -0,0 +1,177 @@
+package gocrud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// errNoScope is returned by nested route handlers invoked without a parent in their context.
+var errNoScope = errors.New("no parent scope")
+
+type scopedRepoKey struct{}
+
+// RegisterNestedRoutes registers GET and POST /{parents}/{pid}/{children} and GET, PUT and
+// DELETE /{parents}/{pid}/{children}/{id}. Every request first checks that the parent exists,
+// responding with 404 if it does not, and is then served by the child repository scoped to rows
+// whose fk column holds the parent key.
+func RegisterNestedRoutes[P Model, PK comparable, C Model, CK comparable](parent *Repository[P, PK], child *Repository[C, CK], fk string, mux *http.ServeMux) {
+	base := fmt.Sprintf("/%s/{pid}/%s", parent.GetTable(), child.GetTable())
+	scoped := func(ctx context.Context) (*Repository[C, CK], error) {
+		repo, ok := ctx.Value(scopedRepoKey{}).(*Repository[C, CK])
+		if !ok {
+			return nil, errNoScope
+		}
+		return repo, nil
+	}
+
+	children := http.NewServeMux()
+	RegisterCreate("POST "+base, children, func(ctx context.Context, in C) (CK, error) {
+		repo, err := scoped(ctx)
+		if err != nil {
+			var zero CK
+			return zero, err
+		}
+		return repo.Create(ctx, in)
+	})
+	RegisterGetAll("GET "+base, children, func(ctx context.Context) ([]C, error) {
+		repo, err := scoped(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return repo.GetAll(ctx)
+	})
+	RegisterGet("GET "+base+"/{id}", children, func(ctx context.Context, id CK) (C, error) {
+		repo, err := scoped(ctx)
+		if err != nil {
+			var zero C
+			return zero, err
+		}
+		return repo.Get(ctx, id)
+	})
+	RegisterUpdate("PUT "+base+"/{id}", children, func(ctx context.Context, in C, id CK) error {
+		repo, err := scoped(ctx)
+		if err != nil {
+			return err
+		}
+		return repo.Update(ctx, in, id)
+	})
+	RegisterDelete("DELETE "+base+"/{id}", children, func(ctx context.Context, id CK) error {
+		repo, err := scoped(ctx)
+		if err != nil {
+			return err
+		}
+		return repo.Delete(ctx, id)
+	})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pid, ok := parentKey(w, r, parent, "pid")
+		if !ok {
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), scopedRepoKey{}, child.WithScope(fk, pid))
+		children.ServeHTTP(w, r.WithContext(ctx))
+	})
+
+	mux.Handle(base, handler)
+	mux.Handle(base+"/{id}", handler)
+}
+
+// RegisterAssociationRoutes registers GET and PUT /{parents}/{id}/{name} to list and replace the
+// related rows, and PUT and DELETE /{parents}/{id}/{name}/{related_id} to link and unlink one.
+// Requests for a parent that does not exist get a 404.
+func RegisterAssociationRoutes[M Model, K comparable, R Model, RK comparable](parent *Repository[M, K], name string, assoc *Association[K, R, RK], mux *http.ServeMux) {
+	base := fmt.Sprintf("/%s/{id}/%s", parent.GetTable(), name)
+
+	mux.HandleFunc("GET "+base, func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parentKey(w, r, parent, "id")
+		if !ok {
+			return
+		}
+
+		out, err := assoc.ListRelated(r.Context(), id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if out == nil {
+			out = []R{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(out); err != nil {
+			log.Printf("failed to encode related resources: %v", err)
+		}
+	})
+
+	mux.HandleFunc("PUT "+base, func(w http.ResponseWriter, r *http.Request) {
+		id, ok := parentKey(w, r, parent, "id")
+		if !ok {
+			return
+		}
+
+		var related []RK
+		if err := json.NewDecoder(r.Body).Decode(&related); err != nil {
+			http.Error(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+
+		if err := assoc.Sync(r.Context(), id, related); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	link := func(f func(context.Context, K, ...RK) error) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			id, ok := parentKey(w, r, parent, "id")
+			if !ok {
+				return
+			}
+			related, err := ParseKey[RK](r.PathValue("related_id"))
+			if err != nil {
+				http.Error(w, "invalid param", http.StatusBadRequest)
+				return
+			}
+
+			if err := f(r.Context(), id, related); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+
+	mux.HandleFunc("PUT "+base+"/{related_id}", link(assoc.Attach))
+	mux.HandleFunc("DELETE "+base+"/{related_id}", link(assoc.Detach))
+}
+
+// parentKey parses the parent key from the path parameter and checks that the parent exists. If
+// not, it writes the error response and returns false.
+func parentKey[P Model, PK comparable](w http.ResponseWriter, r *http.Request, parent *Repository[P, PK], param string) (PK, bool) {
+	id, err := ParseKey[PK](r.PathValue(param))
+	if err != nil {
+		http.Error(w, "invalid param", http.StatusBadRequest)
+		return id, false
+	}
+
+	ok, err := parent.Exists(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return id, false
+	}
+	if !ok {
+		http.Error(w, "resource not found", http.StatusNotFound)
+		return id, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,144 @@
+package gocrud
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// capture matches any argument, recording its value.
+type capture struct {
+	values *[]driver.Value
+}
+
+func (c capture) Match(v driver.Value) bool {
+	*c.values = append(*c.values, v)
+	return true
+}
+
+func TestRoutes_Nested(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	orders := NewGenericRepository(db, "orders", func() *Order { return &Order{} })
+	items := NewGenericRepository(db, "order_items", func() *OrderItem { return &OrderItem{} })
+	mux := http.NewServeMux()
+	RegisterNestedRoutes(orders, items, "order_id", mux)
+
+	orderExists := regexp.QuoteMeta("SELECT COUNT(*) FROM orders WHERE id = ?")
+
+	t.Run("Test nested routes: missing parent", func(t *testing.T) {
+		mock.ExpectQuery(orderExists).WithArgs(9).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/9/order_items", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "resource not found\n", rec.Body.String())
+	})
+
+	t.Run("Test nested routes: child of another parent", func(t *testing.T) {
+		mock.ExpectQuery(orderExists).WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM order_items WHERE id = ? AND order_id = ? LIMIT 1")).
+			WithArgs(7, 4).WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "sku"}))
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/4/order_items/7", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("Test nested routes: POST writes the parent key", func(t *testing.T) {
+		mock.ExpectQuery(orderExists).WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		var args []driver.Value
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO order_items")).
+			WithArgs(capture{&args}, capture{&args}).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+
+		body := strings.NewReader(`{"order_id": 9, "sku": "a"}`)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/orders/4/order_items", body))
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.JSONEq(t, `{"id":3}`, rec.Body.String())
+		assert.ElementsMatch(t, []driver.Value{int64(4), "a"}, args)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRoutes_Association(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	devices := NewGenericRepository(db, "devices", func() *TaggedDevice { return &TaggedDevice{} }, WithDialect(Postgres))
+	tags := NewGenericRepository(db, "tags", func() *Tag { return &Tag{} }, WithDialect(Postgres))
+	deviceTags := ManyToMany(devices, "Tags", tags, "device_tags", "device_id", "tag_id")
+	mux := http.NewServeMux()
+	RegisterAssociationRoutes(devices, "tags", deviceTags, mux)
+
+	deviceExists := regexp.QuoteMeta("SELECT COUNT(*) FROM devices WHERE id = $1")
+
+	t.Run("Test association routes: missing parent", func(t *testing.T) {
+		mock.ExpectQuery(deviceExists).WithArgs(9).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/devices/9/tags", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("Test association routes: list", func(t *testing.T) {
+		mock.ExpectQuery(deviceExists).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM tags WHERE id IN (SELECT tag_id FROM device_tags WHERE device_id = $1) ORDER BY id")).
+			WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/devices/1/tags", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "[]\n", rec.Body.String())
+	})
+
+	t.Run("Test association routes: attach", func(t *testing.T) {
+		mock.ExpectQuery(deviceExists).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT tag_id FROM device_tags WHERE device_id = $1")).
+			WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"tag_id"}))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO device_tags (device_id,tag_id) VALUES ($1,$2)")).
+			WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/devices/1/tags/2", nil))
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("Test association routes: attach to missing parent", func(t *testing.T) {
+		mock.ExpectQuery(deviceExists).WithArgs(9).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/devices/9/tags/2", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
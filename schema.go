@@ -0,0 +1,247 @@
+package gocrud
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Column describes a table column as reported by the database.
+type Column struct {
+	Name       string
+	Type       string
+	NotNull    bool
+	HasDefault bool
+}
+
+// TypeMismatch is a model field whose Go type cannot hold the values of its column.
+type TypeMismatch struct {
+	Column     string
+	ColumnType string
+	FieldType  string
+}
+
+// SchemaError lists the differences between a model and its table found by Validate.
+type SchemaError struct {
+	Table string
+	// Missing are model columns that do not exist in the table.
+	Missing []string
+	// Mismatched are columns whose type is incompatible with the model field.
+	Mismatched []TypeMismatch
+	// Unmapped are NOT NULL columns without a default that the model does not map, so inserts would fail.
+	Unmapped []string
+}
+
+func (e *SchemaError) Error() string {
+	var problems []string
+	if len(e.Missing) > 0 {
+		problems = append(problems, "missing columns: "+strings.Join(e.Missing, ", "))
+	}
+	for _, m := range e.Mismatched {
+		problems = append(problems, fmt.Sprintf("column %s of type %s cannot be scanned into %s", m.Column, m.ColumnType, m.FieldType))
+	}
+	if len(e.Unmapped) > 0 {
+		problems = append(problems, "unmapped NOT NULL columns: "+strings.Join(e.Unmapped, ", "))
+	}
+
+	return fmt.Sprintf("table %s does not match model: %s", e.Table, strings.Join(problems, "; "))
+}
+
+// TableColumns introspects the repository's table using the dialect's ColumnsQuery.
+func (r *Repository[M, K]) TableColumns(ctx context.Context) ([]Column, error) {
+	rows, err := r.db.QueryContext(ctx, r.config.dialect.ColumnsQuery, r.table)
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []Column
+	for rows.Next() {
+		var c Column
+		if err := rows.Scan(&c.Name, &c.Type, &c.NotNull, &c.HasDefault); err != nil {
+			return nil, err
+		}
+		columns = append(columns, c)
+	}
+
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s: %w", r.table, sql.ErrNoRows)
+	}
+
+	return columns, nil
+}
+
+// Validate compares the model with its table and returns a *SchemaError describing missing
+// columns, type incompatibilities and unmapped NOT NULL columns. Call it before serving traffic.
+func (r *Repository[M, K]) Validate(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	columns, err := r.TableColumns(ctx)
+	if err != nil {
+		return err
+	}
+
+	fields := r.fields(r.getConcreteType())
+	if r.config.softDelete {
+		if _, ok := fields[softDeleteColumn]; !ok {
+			fields[softDeleteColumn] = new(*time.Time)
+		}
+	}
+
+	e := &SchemaError{Table: r.table}
+
+	byName := make(map[string]Column, len(columns))
+	for _, c := range columns {
+		byName[c.Name] = c
+		if _, ok := fields[c.Name]; !ok && c.NotNull && !c.HasDefault {
+			e.Unmapped = append(e.Unmapped, c.Name)
+		}
+	}
+
+	for name, p := range fields {
+		c, ok := byName[name]
+		if !ok {
+			e.Missing = append(e.Missing, name)
+			continue
+		}
+
+		if fieldType, ok := compatible(p, c.Type); !ok {
+			e.Mismatched = append(e.Mismatched, TypeMismatch{Column: name, ColumnType: c.Type, FieldType: fieldType})
+		}
+	}
+
+	if len(e.Missing) == 0 && len(e.Mismatched) == 0 && len(e.Unmapped) == 0 {
+		return nil
+	}
+
+	slices.Sort(e.Missing)
+	slices.SortFunc(e.Mismatched, func(a, b TypeMismatch) int { return strings.Compare(a.Column, b.Column) })
+
+	return e
+}
+
+type family int
+
+const (
+	unknownFamily family = iota
+	intFamily
+	floatFamily
+	boolFamily
+	textFamily
+	timeFamily
+	bytesFamily
+	jsonFamily
+	arrayFamily
+)
+
+// sqlFamily classifies a column type by keyword, in the spirit of SQLite type affinity.
+func sqlFamily(columnType string) family {
+	t := strings.ToLower(columnType)
+
+	switch {
+	case strings.HasPrefix(t, "_") || strings.HasSuffix(t, "[]") || t == "array":
+		return arrayFamily
+	case strings.Contains(t, "json"):
+		return jsonFamily
+	case strings.Contains(t, "bool"):
+		return boolFamily
+	case strings.Contains(t, "interval"):
+		return unknownFamily
+	case strings.Contains(t, "int") || strings.Contains(t, "serial"):
+		return intFamily
+	case strings.Contains(t, "char") || strings.Contains(t, "text") || strings.Contains(t, "clob") ||
+		strings.Contains(t, "uuid") || strings.Contains(t, "enum"):
+		return textFamily
+	case strings.Contains(t, "time") || strings.Contains(t, "date"):
+		return timeFamily
+	case strings.Contains(t, "bytea") || strings.Contains(t, "blob") || strings.Contains(t, "binary"):
+		return bytesFamily
+	case strings.Contains(t, "real") || strings.Contains(t, "floa") || strings.Contains(t, "doub") ||
+		strings.Contains(t, "numeric") || strings.Contains(t, "decimal"):
+		return floatFamily
+	default:
+		return unknownFamily
+	}
+}
+
+// goFamilies returns the column families a StructToMap entry can be stored in, or nil if
+// any column type may be valid (e.g. custom scanners and converters).
+func goFamilies(p any) []family {
+	switch c := p.(type) {
+	case jsonColumn:
+		return []family{jsonFamily, textFamily, bytesFamily}
+	case postgresArray:
+		return []family{arrayFamily}
+	case nullZero:
+		return typeFamilies(c.field.Type())
+	case convertedColumn:
+		return nil
+	default:
+	}
+
+	v := reflect.ValueOf(p)
+	if _, ok := p.(sql.Scanner); ok || v.Kind() != reflect.Pointer {
+		return nil
+	}
+
+	return typeFamilies(v.Type().Elem())
+}
+
+func typeFamilies(t reflect.Type) []family {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeFor[time.Time]() {
+		return []family{timeFamily, textFamily}
+	}
+	if reflect.PointerTo(t).Implements(reflect.TypeFor[sql.Scanner]()) {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return []family{intFamily, floatFamily}
+	case reflect.Float32, reflect.Float64:
+		return []family{floatFamily}
+	case reflect.Bool:
+		return []family{boolFamily, intFamily}
+	case reflect.String:
+		return []family{textFamily, jsonFamily, timeFamily}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return []family{bytesFamily, textFamily, jsonFamily}
+		}
+		return []family{arrayFamily, jsonFamily}
+	case reflect.Map, reflect.Struct:
+		return []family{jsonFamily}
+	default:
+		return nil
+	}
+}
+
+// compatible reports whether p can hold values of columnType, along with the field type for reporting.
+func compatible(p any, columnType string) (string, bool) {
+	fieldType := fieldValue(p).Type().String()
+
+	accepted := goFamilies(p)
+	family := sqlFamily(columnType)
+	if accepted == nil || family == unknownFamily {
+		return fieldType, true
+	}
+
+	return fieldType, slices.Contains(accepted, family)
+}
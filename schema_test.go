@@ -0,0 +1,95 @@
+package gocrud
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	columns := []string{"name", "type", "not_null", "has_default"}
+	query := regexp.QuoteMeta(SQLite.ColumnsQuery)
+
+	t.Run("Test Validate() matching table", func(t *testing.T) {
+		repo := NewGenericRepository(db, "devices", func() *ModelWithJSON { return &ModelWithJSON{} }, WithDialect(SQLite))
+
+		mock.ExpectQuery(query).WithArgs("devices").WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("id", "INTEGER", false, true).
+			AddRow("settings", "TEXT", true, false).
+			AddRow("labels", "JSON", false, false).
+			AddRow("notes", "TEXT", false, false))
+
+		assert.NoError(t, repo.Validate(context.Background()))
+	})
+
+	t.Run("Test Validate() mismatched table", func(t *testing.T) {
+		repo := NewGenericRepository(db, "devices", func() *ModelWithReflection { return &ModelWithReflection{} }, WithDialect(SQLite))
+
+		mock.ExpectQuery(query).WithArgs("devices").WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("id", "INTEGER", false, true).
+			AddRow("name", "INTEGER", true, false).
+			AddRow("type", "VARCHAR(20)", true, false).
+			AddRow("chip", "TEXT", false, false).
+			AddRow("owner_id", "INTEGER", true, false))
+
+		err := repo.Validate(context.Background())
+
+		var schemaErr *SchemaError
+		if !errors.As(err, &schemaErr) {
+			t.Fatalf("expected *SchemaError, got %v", err)
+		}
+
+		assert.Equal(t, &SchemaError{
+			Table:      "devices",
+			Missing:    []string{"board", "ip"},
+			Mismatched: []TypeMismatch{{Column: "name", ColumnType: "INTEGER", FieldType: "string"}},
+			Unmapped:   []string{"owner_id"},
+		}, schemaErr)
+		assert.Contains(t, err.Error(), "missing columns: board, ip")
+	})
+
+	t.Run("Test Validate() missing table", func(t *testing.T) {
+		repo := NewGenericRepository(db, "devices", func() *ModelWithReflection { return &ModelWithReflection{} }, WithDialect(SQLite))
+
+		mock.ExpectQuery(query).WithArgs("devices").WillReturnRows(sqlmock.NewRows(columns))
+
+		assert.Error(t, repo.Validate(context.Background()))
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSchema_SQLFamily(t *testing.T) {
+	tests := map[string]family{
+		"INTEGER":                  intFamily,
+		"bigserial":                intFamily,
+		"character varying":        textFamily,
+		"uuid":                     textFamily,
+		"timestamp with time zone": timeFamily,
+		"jsonb":                    jsonFamily,
+		"_text":                    arrayFamily,
+		"bytea":                    bytesFamily,
+		"double precision":         floatFamily,
+		"boolean":                  boolFamily,
+		"interval":                 unknownFamily,
+		"USER-DEFINED":             unknownFamily,
+	}
+
+	for columnType, want := range tests {
+		t.Run("Test sqlFamily "+columnType, func(t *testing.T) {
+			assert.Equal(t, want, sqlFamily(columnType))
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package gocrud
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"iter"
+	"log"
+	"net/http"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Stream iterates over all rows, ordered by primary key, reading one row at a time instead of
+// buffering them like GetAll. Relations are not preloaded. Breaking out of the loop closes the
+// rows; the first error ends the iteration.
+func (r *Repository[M, K]) Stream(ctx context.Context) iter.Seq2[M, error] {
+	b := r.scope(r.builder().Select("*").From(r.table)).OrderBy(r.config.keyColumns...)
+
+	return queryIter(ctx, r.db, r.config, b, r.getConcreteType)
+}
+
+// Iter iterates over the rows of the query one at a time.
+func (q *SelectQuery[D]) Iter(ctx context.Context) iter.Seq2[D, error] {
+	return queryIter(ctx, q.db, q.config, q.b, q.newRow)
+}
+
+// queryIter yields every row returned by b, scanned into a model created by newModel.
+func queryIter[T Model](ctx context.Context, db *sql.DB, c config, b sq.Sqlizer, newModel func() T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		query, args, err := b.ToSql()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+
+		fields, err := rows.Columns()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for rows.Next() {
+			model := newModel()
+			if err := c.set(fields, rows.Scan, model); err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(model, nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// RegisterGetAllStream is the streaming mode of RegisterGetAll: rows from f, e.g.
+// Repository.Stream, are encoded to the response as they are read, with constant memory.
+// The format is negotiated like RegisterGetAll. An error after the first row aborts the response,
+// leaving a truncated body.
+func RegisterGetAllStream[Out any](pattern string, mux *http.ServeMux, f func(context.Context) iter.Seq2[Out, error], opts ...HandlerOption) {
+	c := newHandlerConfig(opts)
+
+	mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		format, ok := negotiateList[Out](r)
+		if !ok {
+			http.Error(w, "unsupported format", http.StatusNotAcceptable)
+			return
+		}
+
+		if c.countOnly(w, r) {
+			return
+		}
+
+		if !c.totalCount(w, r) {
+			return
+		}
+
+		var enc listEncoder
+		for item, err := range f(r.Context()) {
+			if err != nil {
+				if enc == nil {
+					if errors.Is(err, sql.ErrNoRows) {
+						http.Error(w, "resource not found", http.StatusNotFound)
+						return
+					}
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				log.Printf("failed to stream resources: %v", err)
+				return
+			}
+
+			if enc == nil {
+				enc = format.start(w)
+			}
+			if err := enc.encode(item); err != nil {
+				log.Printf("failed to stream resources: %v", err)
+				return
+			}
+		}
+
+		if enc == nil {
+			enc = format.start(w)
+		}
+		if err := enc.end(); err != nil {
+			log.Printf("failed to stream resources: %v", err)
+		}
+	})
+}
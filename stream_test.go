@@ -0,0 +1,141 @@
+package gocrud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_Stream(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewGenericRepository(db, "devices", func() *ModelWithReflection { return &ModelWithReflection{} }, WithSoftDelete())
+
+	t.Run("Test Stream()", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM devices WHERE deleted_at IS NULL ORDER BY id")).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b"))
+
+		var got []*ModelWithReflection
+		for device, err := range repo.Stream(context.Background()) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, device)
+		}
+
+		assert.Equal(t, []*ModelWithReflection{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}, got)
+	})
+
+	t.Run("Test Stream() break closes rows", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM devices WHERE deleted_at IS NULL ORDER BY id")).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b")).
+			RowsWillBeClosed()
+
+		for device := range repo.Stream(context.Background()) {
+			assert.Equal(t, 1, device.ID)
+			break
+		}
+	})
+
+	t.Run("Test Stream() row error", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM devices WHERE deleted_at IS NULL ORDER BY id")).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b").RowError(1, errors.New("connection lost")))
+
+		var ids []int
+		var errs []error
+		for device, err := range repo.Stream(context.Background()) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			ids = append(ids, device.ID)
+		}
+
+		assert.Equal(t, []int{1}, ids)
+		assert.Len(t, errs, 1)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func (mock *genericRepoMock[M]) Stream(context.Context) iter.Seq2[M, error] {
+	return func(yield func(M, error) bool) {
+		for _, model := range mock.models {
+			if !yield(model, nil) {
+				return
+			}
+		}
+		if mock.err != nil {
+			var zero M
+			yield(zero, mock.err)
+		}
+	}
+}
+
+func TestMethod_GetAllStream(t *testing.T) {
+	repo := &genericRepoMock[*Item]{t: t, models: []*Item{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}, table: "item"}
+	mux := http.NewServeMux()
+	RegisterGetAllStream(fmt.Sprintf("GET /%s", repo.GetTable()), mux, repo.Stream)
+
+	t.Run("Test generic method: GetAllStream() - JSON array", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/item", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.JSONEq(t, `[{"id":1,"name":"a","type":"","tag":"","kind":"","ip":""},{"id":2,"name":"b","type":"","tag":"","kind":"","ip":""}]`, rec.Body.String())
+	})
+
+	t.Run("Test generic method: GetAllStream() - NDJSON", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/item", nil)
+		req.Header.Set("Accept", "application/x-ndjson")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "{\"id\":1,\"name\":\"a\",\"type\":\"\",\"tag\":\"\",\"kind\":\"\",\"ip\":\"\"}\n"+
+			"{\"id\":2,\"name\":\"b\",\"type\":\"\",\"tag\":\"\",\"kind\":\"\",\"ip\":\"\"}\n", rec.Body.String())
+	})
+
+	t.Run("Test generic method: GetAllStream() - empty", func(t *testing.T) {
+		mux := http.NewServeMux()
+		RegisterGetAllStream("GET /empty", mux, (&genericRepoMock[*Item]{t: t}).Stream)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/empty", nil))
+
+		assert.Equal(t, "[]\n", rec.Body.String())
+	})
+
+	t.Run("Test generic method: GetAllStream() - error before first row", func(t *testing.T) {
+		mux := http.NewServeMux()
+		RegisterGetAllStream("GET /broken", mux, (&genericRepoMock[*Item]{t: t, err: errors.New("boom")}).Stream)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/broken", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "boom\n", rec.Body.String())
+	})
+
+	t.Run("Test generic method: GetAllStream() - unsupported format", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/item?format=xml", nil))
+
+		assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+	})
+}